@@ -1,3 +1,5 @@
 module github.com/skeletonarmydev/go-kestra
 
-go 1.22rc1
+go 1.23
+
+require gopkg.in/yaml.v3 v3.0.1
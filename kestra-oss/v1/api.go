@@ -0,0 +1,61 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"iter"
+)
+
+//go:generate go run ./internal/mockgen -in api.go -out kestramock/mocks.go
+
+// FlowAPI is the Kestra flow API, implemented by FlowService.
+// Code depending on it can be tested with the mocks of package kestramock.
+type FlowAPI interface {
+	GetAll(ctx context.Context, namespace string) (*[]Flow, *Response, error)
+	Get(ctx context.Context, namespace string, flowID string) (*Flow, *Response, error)
+	GetSource(ctx context.Context, namespace string, flowID string) (string, *Response, error)
+	Revisions(ctx context.Context, namespace string, flowID string) ([]Flow, *Response, error)
+	GetRevision(ctx context.Context, namespace string, flowID string, revision int) (*Flow, *Response, error)
+	Dependencies(ctx context.Context, namespace string, flowID string, transitive bool) (*DependencyGraph, *Response, error)
+	Graph(ctx context.Context, namespace string, flowID string) (*FlowGraph, *Response, error)
+	Search(ctx context.Context, opts *FlowSearchOptions) (*SearchResult, *Response, error)
+	SearchAll(ctx context.Context, opts *FlowSearchOptions) iter.Seq2[Flow, error]
+	Create(ctx context.Context, content string) (*Flow, *Response, error)
+	CreateFromYAML(ctx context.Context, source string) (*Flow, *Response, error)
+	CreateFromFlow(ctx context.Context, flow *Flow) (*Flow, *Response, error)
+	Update(ctx context.Context, namespace string, flowID string, content string) (*Flow, *Response, error)
+	Upsert(ctx context.Context, source string) (*UpsertResult, *Response, error)
+	Delete(ctx context.Context, namespace string, flowID string) (*Response, error)
+	DeleteByIDs(ctx context.Context, flows []FlowRef) (*BulkResult, *Response, error)
+	DeleteByQuery(ctx context.Context, filter *FlowFilter) (*BulkResult, *Response, error)
+	Enable(ctx context.Context, namespace string, flowID string) (*FlowOutcome, *Response, error)
+	Disable(ctx context.Context, namespace string, flowID string) (*FlowOutcome, *Response, error)
+	SetDisabledByIDs(ctx context.Context, flows []FlowRef, disabled bool) (*BulkResult, *Response, error)
+	SetDisabledByQuery(ctx context.Context, filter *FlowFilter, disabled bool) (*BulkResult, *Response, error)
+	Validate(ctx context.Context, sources ...string) ([]FlowValidation, *Response, error)
+	ValidateTask(ctx context.Context, source string) (*FlowValidation, *Response, error)
+	ValidateTrigger(ctx context.Context, source string) (*FlowValidation, *Response, error)
+	ExportByQuery(ctx context.Context, filter *FlowFilter, w io.Writer) (*Response, error)
+	ExportByIDs(ctx context.Context, flows []FlowRef, w io.Writer) (*Response, error)
+	Import(ctx context.Context, r io.Reader) (*Response, error)
+	SyncNamespace(ctx context.Context, namespace string, sources []string, opts *SyncOptions) (*SyncResult, *Response, error)
+}
+
+// ExecutionAPI is the Kestra execution API, implemented by ExecutionService.
+type ExecutionAPI interface {
+	Get(ctx context.Context, executionID string) (*Execution, *Response, error)
+	Create(ctx context.Context, namespace string, flowId string, input map[string]string) (*Execution, *Response, error)
+	Search(ctx context.Context, opts *ExecutionSearchOptions) (*PagedResults[Execution], *Response, error)
+	SearchAll(ctx context.Context, opts *ExecutionSearchOptions) iter.Seq2[Execution, error]
+}
+
+// LogAPI is the Kestra log API, implemented by LogService.
+type LogAPI interface {
+	Get(ctx context.Context, executionID string) (*[]Log, *Response, error)
+}
+
+var (
+	_ FlowAPI      = (*FlowService)(nil)
+	_ ExecutionAPI = (*ExecutionService)(nil)
+	_ LogAPI       = (*LogService)(nil)
+)
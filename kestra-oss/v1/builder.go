@@ -0,0 +1,246 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/skeletonarmydev/go-kestra/kestra-oss/v1/internal/ids"
+)
+
+// NewTask returns a task of the given ID and type. Its properties are set with With.
+func NewTask(id string, taskType string) FlowTask {
+	return FlowTask{ID: id, Type: taskType}
+}
+
+// With returns a copy of the task with the property name set to value.
+func (t FlowTask) With(name string, value interface{}) FlowTask {
+	t.Properties = maps.Clone(t.Properties)
+	if t.Properties == nil {
+		t.Properties = make(map[string]interface{})
+	}
+	t.Properties[name] = value
+	return t
+}
+
+// NewTrigger returns a trigger of the given ID and type. Its properties are set with With.
+func NewTrigger(id string, triggerType string) FlowTrigger {
+	return FlowTrigger{ID: id, Type: triggerType}
+}
+
+// With returns a copy of the trigger with the property name set to value.
+func (t FlowTrigger) With(name string, value interface{}) FlowTrigger {
+	t.Properties = maps.Clone(t.Properties)
+	if t.Properties == nil {
+		t.Properties = make(map[string]interface{})
+	}
+	t.Properties[name] = value
+	return t
+}
+
+// FlowBuilder builds a Flow, validating it locally before it is sent to Kestra:
+//
+//	source, err := kestra.NewFlow("company.team", "hello_world").
+//		Input(kestra.FlowInput{ID: "name", Type: "STRING"}).
+//		Task(kestra.NewTask("log", "io.kestra.plugin.core.log.Log").With("message", "Hello {{ inputs.name }}")).
+//		Label("team", "data").
+//		YAML()
+type FlowBuilder struct {
+	flow Flow
+}
+
+// NewFlow returns a builder of the flow namespace/id.
+func NewFlow(namespace string, id string) *FlowBuilder {
+	return &FlowBuilder{flow: Flow{ID: id, Namespace: namespace}}
+}
+
+// Description sets the description of the flow.
+func (b *FlowBuilder) Description(description string) *FlowBuilder {
+	b.flow.Description = description
+	return b
+}
+
+// Label adds a label to the flow, replacing any label with the same key.
+func (b *FlowBuilder) Label(key string, value string) *FlowBuilder {
+	for i, label := range b.flow.Labels {
+		if label.Key == key {
+			b.flow.Labels[i].Value = value
+			return b
+		}
+	}
+	b.flow.Labels = append(b.flow.Labels, Label{Key: key, Value: value})
+	return b
+}
+
+// Variable sets a variable of the flow.
+func (b *FlowBuilder) Variable(name string, value interface{}) *FlowBuilder {
+	if b.flow.Variables == nil {
+		b.flow.Variables = make(map[string]interface{})
+	}
+	b.flow.Variables[name] = value
+	return b
+}
+
+// Input adds inputs to the flow.
+func (b *FlowBuilder) Input(inputs ...FlowInput) *FlowBuilder {
+	b.flow.Inputs = append(b.flow.Inputs, inputs...)
+	return b
+}
+
+// Task adds tasks to the flow.
+func (b *FlowBuilder) Task(tasks ...FlowTask) *FlowBuilder {
+	b.flow.Tasks = append(b.flow.Tasks, tasks...)
+	return b
+}
+
+// OnError adds tasks run when the flow fails.
+func (b *FlowBuilder) OnError(tasks ...FlowTask) *FlowBuilder {
+	b.flow.Errors = append(b.flow.Errors, tasks...)
+	return b
+}
+
+// Finally adds tasks run at the end of the flow, whatever its outcome.
+func (b *FlowBuilder) Finally(tasks ...FlowTask) *FlowBuilder {
+	b.flow.Finally = append(b.flow.Finally, tasks...)
+	return b
+}
+
+// Trigger adds triggers to the flow.
+func (b *FlowBuilder) Trigger(triggers ...FlowTrigger) *FlowBuilder {
+	b.flow.Triggers = append(b.flow.Triggers, triggers...)
+	return b
+}
+
+// PluginDefault adds plugin defaults to the flow.
+func (b *FlowBuilder) PluginDefault(defaults ...PluginDefault) *FlowBuilder {
+	b.flow.PluginDefaults = append(b.flow.PluginDefaults, defaults...)
+	return b
+}
+
+// Concurrency limits the number of concurrent executions of the flow. behavior is "QUEUE", "CANCEL" or "FAIL".
+func (b *FlowBuilder) Concurrency(limit int, behavior string) *FlowBuilder {
+	b.flow.Concurrency = &Concurrency{Limit: limit, Behavior: behavior}
+	return b
+}
+
+// Retry sets the retry policy of the flow.
+func (b *FlowBuilder) Retry(retry Retry) *FlowBuilder {
+	b.flow.Retry = &retry
+	return b
+}
+
+// Disabled sets whether the flow is disabled.
+func (b *FlowBuilder) Disabled(disabled bool) *FlowBuilder {
+	b.flow.Disabled = disabled
+	return b
+}
+
+// Build validates the flow and returns it. Every problem found is returned, joined in a single error.
+// The returned flow can be encoded with encoding/json for FlowService.Create.
+func (b *FlowBuilder) Build() (*Flow, error) {
+	// copy what the builder may still change, so that the builder can be reused for similar flows
+	flow := b.flow
+	flow.Labels = slices.Clone(flow.Labels)
+	flow.Variables = maps.Clone(flow.Variables)
+	flow.Inputs = slices.Clip(flow.Inputs)
+	flow.Tasks = slices.Clip(flow.Tasks)
+	flow.Errors = slices.Clip(flow.Errors)
+	flow.Finally = slices.Clip(flow.Finally)
+	flow.Triggers = slices.Clip(flow.Triggers)
+	flow.PluginDefaults = slices.Clip(flow.PluginDefaults)
+	if err := validateFlowStructure(&flow); err != nil {
+		return nil, err
+	}
+	return &flow, nil
+}
+
+// YAML validates the flow and renders its canonical YAML source, as MarshalFlowYAML does.
+func (b *FlowBuilder) YAML() (string, error) {
+	flow, err := b.Build()
+	if err != nil {
+		return "", err
+	}
+	return MarshalFlowYAML(flow)
+}
+
+// validateFlowStructure checks the IDs and the structure of flow as Kestra would, without
+// validating the properties of its tasks and triggers.
+func validateFlowStructure(flow *Flow) error {
+	var errs []error
+	if !ids.Namespace.MatchString(flow.Namespace) || len(flow.Namespace) > ids.MaxLength {
+		errs = append(errs, fmt.Errorf("kestra: namespace %q must match %s and be at most %d characters", flow.Namespace, ids.Namespace, ids.MaxLength))
+	}
+	if !ids.Flow.MatchString(flow.ID) || len(flow.ID) > ids.MaxLength {
+		errs = append(errs, fmt.Errorf("kestra: flow id %q must match %s and be at most %d characters", flow.ID, ids.Flow, ids.MaxLength))
+	}
+
+	seenLabels := make(map[string]bool)
+	for _, label := range flow.Labels {
+		if label.Key == "" {
+			errs = append(errs, errors.New("kestra: label keys must not be empty"))
+		} else if seenLabels[label.Key] {
+			errs = append(errs, fmt.Errorf("kestra: duplicate label %q", label.Key))
+		}
+		seenLabels[label.Key] = true
+	}
+
+	seenInputs := make(map[string]bool)
+	for _, input := range flow.Inputs {
+		if !ids.Input.MatchString(input.ID) || len(input.ID) > ids.MaxLength {
+			errs = append(errs, fmt.Errorf("kestra: input id %q must match %s and be at most %d characters", input.ID, ids.Input, ids.MaxLength))
+		}
+		if input.Type == "" {
+			errs = append(errs, fmt.Errorf("kestra: input %q: type is required", input.ID))
+		} else if _, err := input.Default(); err != nil {
+			errs = append(errs, err)
+		}
+		if seenInputs[input.ID] {
+			errs = append(errs, fmt.Errorf("kestra: duplicate input %q", input.ID))
+		}
+		seenInputs[input.ID] = true
+	}
+
+	if len(flow.Tasks) == 0 {
+		errs = append(errs, errors.New("kestra: a flow needs at least one task"))
+	}
+	seenTasks := make(map[string]bool)
+	for _, tasks := range [][]FlowTask{flow.Tasks, flow.Errors, flow.Finally} {
+		errs = append(errs, validateTasks(tasks, seenTasks)...)
+	}
+
+	seenTriggers := make(map[string]bool)
+	for _, trigger := range flow.Triggers {
+		errs = append(errs, validateID("trigger", trigger.ID, trigger.Type, seenTriggers)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateTasks checks tasks and their subtasks. Task IDs must be unique across the whole flow.
+func validateTasks(tasks []FlowTask, seen map[string]bool) []error {
+	var errs []error
+	for _, task := range tasks {
+		errs = append(errs, validateID("task", task.ID, task.Type, seen)...)
+		for _, subtasks := range [][]FlowTask{task.Tasks, task.Errors, task.Finally} {
+			errs = append(errs, validateTasks(subtasks, seen)...)
+		}
+	}
+	return errs
+}
+
+// validateID checks the ID and type of a task or a trigger, and that the ID was not seen before.
+func validateID(kind string, id string, typ string, seen map[string]bool) []error {
+	var errs []error
+	if !ids.Task.MatchString(id) || len(id) > ids.MaxLength {
+		errs = append(errs, fmt.Errorf("kestra: %s id %q must match %s and be at most %d characters", kind, id, ids.Task, ids.MaxLength))
+	}
+	if typ == "" {
+		errs = append(errs, fmt.Errorf("kestra: %s %q: type is required", kind, id))
+	}
+	if seen[id] {
+		errs = append(errs, fmt.Errorf("kestra: duplicate %s id %q", kind, id))
+	}
+	seen[id] = true
+	return errs
+}
@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFlowBuilder_YAML(t *testing.T) {
+	got, err := NewFlow("company.team", "hello_world").
+		Description("Hello World").
+		Label("team", "data").
+		Label("env", "dev").
+		Label("env", "prod").
+		Input(FlowInput{ID: "name", Type: "STRING", Defaults: "world"}).
+		Variable("greeting", "Hello").
+		Task(NewTask("log", "io.kestra.plugin.core.log.Log").With("message", "{{ vars.greeting }} {{ inputs.name }}")).
+		OnError(NewTask("alert", "io.kestra.plugin.core.log.Log").With("message", "failed").With("level", "ERROR")).
+		Trigger(NewTrigger("daily", "io.kestra.plugin.core.trigger.Schedule").With("cron", "0 9 * * *")).
+		Concurrency(1, "QUEUE").
+		YAML()
+	if err != nil {
+		t.Fatalf("YAML() error = %v", err)
+	}
+
+	want := `id: hello_world
+namespace: company.team
+description: Hello World
+labels:
+  - key: team
+    value: data
+  - key: env
+    value: prod
+inputs:
+  - id: name
+    type: STRING
+    defaults: world
+variables:
+  greeting: Hello
+tasks:
+  - id: log
+    type: io.kestra.plugin.core.log.Log
+    message: '{{ vars.greeting }} {{ inputs.name }}'
+errors:
+  - id: alert
+    type: io.kestra.plugin.core.log.Log
+    level: ERROR
+    message: failed
+triggers:
+  - id: daily
+    type: io.kestra.plugin.core.trigger.Schedule
+    cron: 0 9 * * *
+concurrency:
+  limit: 1
+  behavior: QUEUE
+`
+	if got != want {
+		t.Errorf("YAML() got =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFlowBuilder_Build(t *testing.T) {
+	log := NewTask("log", "io.kestra.plugin.core.log.Log")
+
+	tests := []struct {
+		name    string
+		builder *FlowBuilder
+		wantErr []string
+	}{
+		{"valid flow", NewFlow("company.team", "hello_world").Task(log), nil},
+		{"invalid namespace and ID", NewFlow("Company", "hello world").Task(log),
+			[]string{`namespace "Company"`, `flow id "hello world"`}},
+		{"no task", NewFlow("company.team", "hello_world"), []string{"at least one task"}},
+		{"invalid task", NewFlow("company.team", "hello_world").Task(NewTask("my.task", "")),
+			[]string{`task id "my.task"`, `task "my.task": type is required`}},
+		{"duplicate nested task", NewFlow("company.team", "hello_world").
+			Task(FlowTask{ID: "parallel", Type: "io.kestra.plugin.core.flow.Parallel", Tasks: []FlowTask{log}}).
+			Finally(log),
+			[]string{`duplicate task id "log"`}},
+		{"invalid inputs and triggers", NewFlow("company.team", "hello_world").Task(log).
+			Input(FlowInput{ID: "a"}, FlowInput{ID: "a", Type: "STRING"}).
+			Trigger(NewTrigger("t", "Schedule"), NewTrigger("t", "Schedule")),
+			[]string{`input "a": type is required`, `duplicate input "a"`, `duplicate trigger id "t"`}},
+		{"invalid default", NewFlow("company.team", "hello_world").Task(log).
+			Input(FlowInput{ID: "count", Type: "INT", Defaults: "ten"}),
+			[]string{`input "count": default "ten" is not an integer`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flow, err := tt.builder.Build()
+			if tt.wantErr == nil {
+				if err != nil || flow == nil {
+					t.Errorf("Build() got = %v, error = %v", flow, err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Build() error = nil, want %v", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Build() error = %v, want %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFlowBuilder_reuse(t *testing.T) {
+	base := NewFlow("company.team", "hello_world").Label("env", "dev").Task(NewTask("log", "io.kestra.plugin.core.log.Log"))
+	first, _ := base.Build()
+	base.Label("env", "prod").Task(NewTask("other", "io.kestra.plugin.core.log.Log"))
+
+	if v, _ := first.Labels.Get("env"); v != "dev" || len(first.Tasks) != 1 {
+		t.Errorf("built flow changed with the builder: %v", first)
+	}
+}
+
+func TestFlowTask_With(t *testing.T) {
+	base := NewTask("log", "io.kestra.plugin.core.log.Log").With("message", "a")
+	other := base.With("message", "b")
+	if base.Properties["message"] != "a" || other.Properties["message"] != "b" {
+		t.Errorf("With() changed the original task: %v, %v", base.Properties, other.Properties)
+	}
+}
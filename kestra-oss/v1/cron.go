@@ -0,0 +1,236 @@
+package v1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchYears bounds the search of the next fire time of a cron expression that may never match,
+// such as "0 0 30 2 *".
+const cronSearchYears = 10
+
+// cronNicknames are the predefined schedules accepted in place of the fields of an expression.
+var cronNicknames = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	cronMonthNames = map[string]int{"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6, "JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12}
+	cronDayNames   = map[string]int{"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6}
+)
+
+// Cron is a parsed cron expression, as used by Schedule triggers.
+type Cron struct {
+	// each field is a set of bits, one per allowed value
+	seconds, minutes, hours, days, months, weekdays uint64
+	// when both days and weekdays are restricted, a time matches if either matches, as in Vixie cron
+	dayOrWeekday bool
+}
+
+// ParseCron parses a Unix cron expression: minute, hour, day of month, month and day of week, or one of
+// @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly. With withSeconds, the expression
+// starts with a seconds field, as Kestra Schedule triggers allow with their withSeconds property.
+// Fields accept *, ?, lists, ranges, steps, and month and day names (JAN, MON...).
+func ParseCron(expr string, withSeconds bool) (*Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) == 1 && strings.HasPrefix(fields[0], "@") {
+		nickname, ok := cronNicknames[strings.ToLower(fields[0])]
+		if !ok {
+			return nil, fmt.Errorf("kestra: cron %q: unknown nickname", expr)
+		}
+		fields = strings.Fields(nickname)
+		if withSeconds {
+			fields = append([]string{"0"}, fields...)
+		}
+	}
+
+	want := 5
+	if withSeconds {
+		want = 6
+	}
+	if len(fields) != want {
+		return nil, fmt.Errorf("kestra: cron %q: expected %d fields, got %d", expr, want, len(fields))
+	}
+	if !withSeconds {
+		fields = append([]string{"0"}, fields...)
+	}
+
+	c := new(Cron)
+	var err error
+	parse := func(dst *uint64, field string, min, max int, names map[string]int, name string) {
+		if err != nil {
+			return
+		}
+		if *dst, err = parseCronField(field, min, max, names); err != nil {
+			err = fmt.Errorf("kestra: cron %q: %s: %w", expr, name, err)
+		}
+	}
+	parse(&c.seconds, fields[0], 0, 59, nil, "seconds")
+	parse(&c.minutes, fields[1], 0, 59, nil, "minutes")
+	parse(&c.hours, fields[2], 0, 23, nil, "hours")
+	parse(&c.days, fields[3], 1, 31, nil, "day of month")
+	parse(&c.months, fields[4], 1, 12, cronMonthNames, "month")
+	parse(&c.weekdays, fields[5], 0, 7, cronDayNames, "day of week")
+	if err != nil {
+		return nil, err
+	}
+
+	// 7 is another name for Sunday
+	if c.weekdays&(1<<7) != 0 {
+		c.weekdays = c.weekdays&^(1<<7) | 1
+	}
+	c.dayOrWeekday = !isCronWildcard(fields[3]) && !isCronWildcard(fields[5])
+	return c, nil
+}
+
+// isCronWildcard reports whether a field allows every value, not counting steps.
+func isCronWildcard(field string) bool {
+	return strings.HasPrefix(field, "*") || strings.HasPrefix(field, "?")
+}
+
+// parseCronField parses a comma-separated list of values, ranges and steps into a set of bits.
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+		}
+
+		var from, to int
+		switch {
+		case rng == "*" || rng == "?":
+			from, to = min, max
+		case strings.Contains(rng, "-"):
+			fromText, toText, _ := strings.Cut(rng, "-")
+			var err error
+			if from, err = parseCronValue(fromText, min, max, names); err != nil {
+				return 0, err
+			}
+			if to, err = parseCronValue(toText, min, max, names); err != nil {
+				return 0, err
+			}
+			if to < from {
+				return 0, fmt.Errorf("invalid range %q", rng)
+			}
+		default:
+			var err error
+			if from, err = parseCronValue(rng, min, max, names); err != nil {
+				return 0, err
+			}
+			to = from
+			if hasStep {
+				to = max
+			}
+		}
+
+		for v := from; v <= to; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+func parseCronValue(text string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToUpper(text)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", text)
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, min, max)
+	}
+	return v, nil
+}
+
+// Next returns the first time strictly after t matching the expression, in the location of t, or the zero
+// time if there is none in the following years. Local times skipped by a daylight saving time change
+// never match, and local times repeated by one match once, unless the expression fires every hour.
+func (c *Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Second).Add(time.Second)
+	limit := t.Year() + cronSearchYears
+
+	for t.Year() <= limit {
+		var next time.Time
+		switch {
+		case c.months&(1<<int(t.Month())) == 0:
+			next = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.matchesDay(t):
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case c.hours&(1<<t.Hour()) == 0:
+			next = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case c.minutes&(1<<t.Minute()) == 0:
+			next = t.Add(time.Minute - time.Duration(t.Second())*time.Second)
+		case c.seconds&(1<<t.Second()) == 0:
+			next = t.Add(time.Second)
+		default:
+			if !c.repeatsWallClock(t) {
+				return t
+			}
+			next = t.Add(time.Second)
+		}
+		// around daylight saving time changes, the next local day or hour may not be later
+		if !next.After(t) {
+			next = t.Add(time.Second)
+		}
+		t = next
+	}
+	return time.Time{}
+}
+
+// repeatsWallClock reports whether the wall clock of t already matched earlier, in the hour repeated when
+// daylight saving time ends. As in Vixie cron, such times are skipped so that a job runs once, unless the
+// expression fires every hour.
+func (c *Cron) repeatsWallClock(t time.Time) bool {
+	if c.hours == 1<<24-1 {
+		return false
+	}
+	_, offset := t.Zone()
+	_, before := t.Add(-3 * time.Hour).Zone()
+	if before <= offset {
+		return false
+	}
+	earlier := t.Add(-time.Duration(before-offset) * time.Second)
+	return earlier.Format(time.DateTime) == t.Format(time.DateTime)
+}
+
+// NextN returns the n first times strictly after t matching the expression, in the location of t.
+// It returns fewer times if the expression stops matching, as Next does, and nil if n is not positive.
+func (c *Cron) NextN(t time.Time, n int) []time.Time {
+	if n <= 0 {
+		return nil
+	}
+	times := make([]time.Time, 0, n)
+	for len(times) < n {
+		t = c.Next(t)
+		if t.IsZero() {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+func (c *Cron) matchesDay(t time.Time) bool {
+	day := c.days&(1<<t.Day()) != 0
+	weekday := c.weekdays&(1<<int(t.Weekday())) != 0
+	if c.dayOrWeekday {
+		return day || weekday
+	}
+	return day && weekday
+}
@@ -0,0 +1,119 @@
+package v1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		name        string
+		expr        string
+		withSeconds bool
+		wantErr     bool
+	}{
+		{"should parse an expression", "*/15 9-17 * * MON-FRI", false, false},
+		{"should parse an expression with seconds", "30 0 12 ? * 1,3,5", true, false},
+		{"should parse a nickname", "@daily", false, false},
+		{"should parse a nickname with seconds", "@hourly", true, false},
+		{"should not parse an unknown nickname", "@often", false, true},
+		{"should not parse too few fields", "0 0 * *", false, true},
+		{"should not parse seconds without withSeconds", "0 0 0 * * *", false, true},
+		{"should not parse an out of range value", "0 24 * * *", false, true},
+		{"should not parse an invalid step", "*/0 * * * *", false, true},
+		{"should not parse an inverted range", "0 0 * * FRI-MON", false, true},
+		{"should not parse an unknown name", "0 0 * FOO *", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCron(tt.expr, tt.withSeconds)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCron() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCron_NextN(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("no time zone database: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no time zone database: %v", err)
+	}
+	date := func(loc *time.Location, month time.Month, day, hour, min, sec int) time.Time {
+		return time.Date(2024, month, day, hour, min, sec, 0, loc)
+	}
+	// New York springs forward on 2026-03-08 at 2:00 EST and falls back on 2026-11-01 at 2:00 EDT
+	est := func(day, hour, min int) time.Time {
+		return time.Date(2026, 11, day, hour, min, 0, 0, time.FixedZone("EST", -5*3600)).In(newYork)
+	}
+	edt := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, time.FixedZone("EDT", -4*3600)).In(newYork)
+	}
+
+	tests := []struct {
+		name        string
+		expr        string
+		withSeconds bool
+		after       time.Time
+		n           int
+		want        []time.Time
+	}{
+		{"should step minutes", "*/20 * * * *", false, date(time.UTC, 1, 1, 10, 5, 0), 3,
+			[]time.Time{date(time.UTC, 1, 1, 10, 20, 0), date(time.UTC, 1, 1, 10, 40, 0), date(time.UTC, 1, 1, 11, 0, 0)}},
+		{"should be strictly after", "0 10 * * *", false, date(time.UTC, 1, 1, 10, 0, 0), 1,
+			[]time.Time{date(time.UTC, 1, 2, 10, 0, 0)}},
+		{"should list week days", "0 9 * * MON,FRI", false, date(time.UTC, 1, 1, 12, 0, 0), 3,
+			[]time.Time{date(time.UTC, 1, 5, 9, 0, 0), date(time.UTC, 1, 8, 9, 0, 0), date(time.UTC, 1, 12, 9, 0, 0)}},
+		{"should treat 7 as Sunday", "0 0 * * 7", false, date(time.UTC, 1, 1, 0, 0, 0), 1,
+			[]time.Time{date(time.UTC, 1, 7, 0, 0, 0)}},
+		{"should match day of month or week day", "0 0 13 * FRI", false, date(time.UTC, 9, 1, 0, 0, 0), 3,
+			[]time.Time{date(time.UTC, 9, 6, 0, 0, 0), date(time.UTC, 9, 13, 0, 0, 0), date(time.UTC, 9, 20, 0, 0, 0)}},
+		{"should skip short months", "0 0 31 * *", false, date(time.UTC, 1, 31, 0, 0, 0), 2,
+			[]time.Time{date(time.UTC, 3, 31, 0, 0, 0), date(time.UTC, 5, 31, 0, 0, 0)}},
+		{"should use nicknames", "@yearly", false, date(time.UTC, 2, 1, 0, 0, 0), 1,
+			[]time.Time{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		{"should use seconds", "*/30 * * * * *", true, date(time.UTC, 1, 1, 0, 0, 10), 2,
+			[]time.Time{date(time.UTC, 1, 1, 0, 0, 30), date(time.UTC, 1, 1, 0, 1, 0)}},
+		{"should use the location of the time", "0 9 * * *", false, date(time.UTC, 1, 1, 9, 0, 0).In(paris), 1,
+			[]time.Time{date(paris, 1, 2, 9, 0, 0)}},
+		{"should skip times missing on a DST change", "30 2 * * *", false, date(paris, 3, 30, 12, 0, 0), 2,
+			[]time.Time{date(paris, 4, 1, 2, 30, 0), date(paris, 4, 2, 2, 30, 0)}},
+		{"should fire in both repeated hours of a DST change", "0 * * * *", false, date(paris, 10, 27, 1, 30, 0), 3,
+			[]time.Time{date(paris, 10, 27, 1, 0, 0).Add(time.Hour), date(paris, 10, 27, 1, 0, 0).Add(2 * time.Hour), date(paris, 10, 27, 3, 0, 0)}},
+		{"should fire once in the hour repeated when DST ends", "30 1 * * *", false, edt(10, 31, 12, 0), 3,
+			[]time.Time{edt(11, 1, 1, 30), est(2, 1, 30), est(3, 1, 30)}},
+		{"should not fire again in the repeated hour", "30 1 * * *", false, edt(11, 1, 1, 45), 1,
+			[]time.Time{est(2, 1, 30)}},
+		{"should fire every half hour once in the repeated hour", "*/30 1-2 * * *", false, edt(11, 1, 0, 45), 5,
+			[]time.Time{edt(11, 1, 1, 0), edt(11, 1, 1, 30), est(1, 2, 0), est(1, 2, 30), est(2, 1, 0)}},
+		{"should skip times missing when DST starts", "30 2 * * *", false, time.Date(2026, 3, 7, 12, 0, 0, 0, newYork), 2,
+			[]time.Time{time.Date(2026, 3, 9, 2, 30, 0, 0, newYork), time.Date(2026, 3, 10, 2, 30, 0, 0, newYork)}},
+		{"should fire every hour across the start of DST", "0 * * * *", false, time.Date(2026, 3, 8, 0, 30, 0, 0, newYork), 3,
+			[]time.Time{time.Date(2026, 3, 8, 1, 0, 0, 0, newYork), edt(3, 8, 3, 0), edt(3, 8, 4, 0)}},
+		{"should return no time for a negative count", "0 0 * * *", false, date(time.UTC, 1, 1, 0, 0, 0), -1,
+			[]time.Time{}},
+		{"should return no time for an impossible date", "0 0 30 2 *", false, date(time.UTC, 1, 1, 0, 0, 0), 1,
+			[]time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr, tt.withSeconds)
+			if err != nil {
+				t.Fatalf("ParseCron() error = %v", err)
+			}
+			got := cron.NextN(tt.after, tt.n)
+			if len(got) != len(tt.want) {
+				t.Fatalf("NextN() got = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) || got[i].Location() != tt.after.Location() {
+					t.Errorf("NextN()[%d] got = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
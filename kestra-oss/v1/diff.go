@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change of a unified diff.
+const diffContext = 3
+
+// DiffRevisions returns the unified diff of the sources of two revisions of a flow, as returned by
+// GetRevision or Revisions, or an empty string if the sources are the same.
+func DiffRevisions(from, to *Flow) string {
+	return UnifiedDiff(revisionLabel(from), revisionLabel(to), from.Source, to.Source)
+}
+
+func revisionLabel(flow *Flow) string {
+	return fmt.Sprintf("%s/%s revision %s", flow.Namespace, flow.ID, flow.Revision)
+}
+
+// diffOp is an operation of an edit script: ' ' keeps a line, '-' deletes it and '+' inserts it.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// UnifiedDiff returns the unified diff (as printed by diff -u) turning from into to, labelled with
+// fromLabel and toLabel, or an empty string if they are the same.
+func UnifiedDiff(fromLabel, toLabel, from, to string) string {
+	a, b := splitLines(from), splitLines(to)
+	ops := diffLines(a, b)
+
+	var sb strings.Builder
+	// aLine and bLine are the 1-based line numbers of the next operation
+	aLine, bLine := 1, 1
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			aLine++
+			bLine++
+			continue
+		}
+
+		// a hunk starts diffContext lines before the change, and ends once more than
+		// 2*diffContext unchanged lines follow the last change
+		start := max(i-diffContext, 0)
+		for j := start; j < i; j++ {
+			aLine--
+			bLine--
+		}
+		end, unchanged := i, 0
+		for j := i; j < len(ops) && unchanged <= 2*diffContext; j++ {
+			if ops[j].kind == ' ' {
+				unchanged++
+			} else {
+				unchanged, end = 0, j+1
+			}
+		}
+		end = min(end+diffContext, len(ops))
+
+		aCount, bCount := 0, 0
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				aCount++
+			}
+			if op.kind != '-' {
+				bCount++
+			}
+		}
+
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromLabel, toLabel)
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aLine, aCount), hunkRange(bLine, bCount))
+		for _, op := range ops[start:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			sb.WriteByte('\n')
+		}
+
+		aLine += aCount
+		bLine += bCount
+		i = end
+	}
+
+	return sb.String()
+}
+
+// hunkRange formats the range of a hunk header, as diff -u does.
+func hunkRange(line, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", line-1)
+	case 1:
+		return fmt.Sprintf("%d", line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+// splitLines splits s into lines, without their line endings.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines returns an edit script turning a into b, based on their longest common subsequence.
+func diffLines(a, b []string) []diffOp {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	const (
+		old = "id: hello\nnamespace: tutorial\ndescription: old\ntasks:\n  - id: a\n    type: Log\n  - id: b\n    type: Log\n  - id: c\n    type: Log\n  - id: d\n    type: Log\n  - id: e\n    type: Log\n"
+		new = "id: hello\nnamespace: tutorial\ndescription: new\ntasks:\n  - id: a\n    type: Log\n  - id: b\n    type: Log\n  - id: c\n    type: Log\n  - id: d\n    type: Log\n  - id: e\n    type: Log\n    message: hi\n"
+	)
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{"same sources", old, old, ""},
+		{"two hunks", old, new, `--- A
++++ B
+@@ -1,6 +1,6 @@
+ id: hello
+ namespace: tutorial
+-description: old
++description: new
+ tasks:
+   - id: a
+     type: Log
+@@ -12,3 +12,4 @@
+     type: Log
+   - id: e
+     type: Log
++    message: hi
+`},
+		{"merged hunks", "a\nb\nc\nd\ne\nf\ng\nh\n", "A\nb\nc\nd\ne\nf\ng\nH\n", `--- A
++++ B
+@@ -1,8 +1,8 @@
+-a
++A
+ b
+ c
+ d
+ e
+ f
+ g
+-h
++H
+`},
+		{"from empty", "", "a\nb\n", "--- A\n+++ B\n@@ -0,0 +1,2 @@\n+a\n+b\n"},
+		{"to empty", "a\n", "", "--- A\n+++ B\n@@ -1 +0,0 @@\n-a\n"},
+		{"final newline is ignored", "a\nb", "a\nc\n", "--- A\n+++ B\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnifiedDiff("A", "B", tt.from, tt.to); got != tt.want {
+				t.Errorf("UnifiedDiff() got =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffRevisions(t *testing.T) {
+	from := &Flow{ID: "hello", Namespace: "tutorial", Revision: "1", Source: "id: hello\ndescription: old\n"}
+	to := &Flow{ID: "hello", Namespace: "tutorial", Revision: "2", Source: "id: hello\ndescription: new\n"}
+
+	want := "--- tutorial/hello revision 1\n+++ tutorial/hello revision 2\n@@ -1,2 +1,2 @@\n id: hello\n-description: old\n+description: new\n"
+	if got := DiffRevisions(from, to); got != want {
+		t.Errorf("DiffRevisions() got =\n%s\nwant\n%s", got, want)
+	}
+}
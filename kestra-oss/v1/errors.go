@@ -0,0 +1,201 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors matched by *APIError through errors.Is, based on the HTTP status code
+// returned by Kestra.
+var (
+	ErrBadRequest    = errors.New("kestra: bad request")
+	ErrUnauthorized  = errors.New("kestra: unauthorized")
+	ErrForbidden     = errors.New("kestra: forbidden")
+	ErrNotFound      = errors.New("kestra: not found")
+	ErrConflict      = errors.New("kestra: conflict")
+	ErrUnprocessable = errors.New("kestra: unprocessable entity")
+	ErrServer        = errors.New("kestra: server error")
+
+	// ErrUnsupported is matched by *UnsupportedError.
+	ErrUnsupported = errors.New("kestra: not supported by server")
+)
+
+// ConstraintViolation is a single validation error reported by Kestra
+// in the "_embedded.errors" part of an error response.
+type ConstraintViolation struct {
+	Message string `json:"message,omitempty" structs:"message,omitempty"`
+	Path    string `json:"path,omitempty" structs:"path,omitempty"`
+}
+
+// UnmarshalJSON decodes a violation, tolerating a path sent as a list of segments.
+func (v *ConstraintViolation) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Message string          `json:"message"`
+		Path    json.RawMessage `json:"path"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	v.Message = raw.Message
+	v.Path = ""
+	if len(raw.Path) == 0 || string(raw.Path) == "null" {
+		return nil
+	}
+
+	var path string
+	if err := json.Unmarshal(raw.Path, &path); err == nil {
+		v.Path = path
+		return nil
+	}
+	var segments []interface{}
+	if err := json.Unmarshal(raw.Path, &segments); err == nil {
+		parts := make([]string, 0, len(segments))
+		for _, s := range segments {
+			parts = append(parts, fmt.Sprint(s))
+		}
+		v.Path = strings.Join(parts, ".")
+	}
+
+	return nil
+}
+
+// APIError is returned by CheckResponse (and therefore by every service method) when
+// Kestra answers with a non-2xx status code.
+type APIError struct {
+	// HTTP response that caused this error. Its body has already been read into Body.
+	Response *http.Response
+
+	StatusCode int
+	// Message is the top level error message sent by Kestra, or the HTTP status text
+	// when the body could not be decoded.
+	Message string
+	// Path is the API path the error refers to, when reported by Kestra.
+	Path       string
+	Violations []ConstraintViolation
+	// Body is the raw response body.
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	method, path := "", e.Path
+	if e.Response != nil && e.Response.Request != nil {
+		method = e.Response.Request.Method
+		if path == "" {
+			path = e.Response.Request.URL.Path
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("kestra: ")
+	if method != "" {
+		b.WriteString(method + " ")
+	}
+	if path != "" {
+		b.WriteString(path + ": ")
+	}
+	fmt.Fprintf(&b, "%d %s", e.StatusCode, e.Message)
+	for _, v := range e.Violations {
+		if v.Message == "" || v.Message == e.Message {
+			continue
+		}
+		b.WriteString("; ")
+		if v.Path != "" {
+			b.WriteString(v.Path + ": ")
+		}
+		b.WriteString(v.Message)
+	}
+
+	return b.String()
+}
+
+// Is maps the status code of the error to one of the sentinel errors of this package,
+// so that errors.Is(err, ErrNotFound) can be used instead of inspecting status codes.
+func (e *APIError) Is(target error) bool {
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return target == ErrBadRequest
+	case http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case http.StatusForbidden:
+		return target == ErrForbidden
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusConflict:
+		return target == ErrConflict
+	case http.StatusUnprocessableEntity:
+		return target == ErrUnprocessable
+	}
+
+	return e.StatusCode >= 500 && target == ErrServer
+}
+
+// kestraError is the JSON error body returned by Kestra.
+type kestraError struct {
+	Message string `json:"message"`
+	Links   struct {
+		Self struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"_links"`
+	Embedded struct {
+		Errors []ConstraintViolation `json:"errors"`
+	} `json:"_embedded"`
+}
+
+// newAPIError builds an APIError from r, consuming its body. The body is replaced
+// with an in-memory copy so that it can still be read by the caller.
+func newAPIError(r *http.Response) *APIError {
+	apiErr := &APIError{
+		Response:   r,
+		StatusCode: r.StatusCode,
+	}
+
+	if r.Body != nil {
+		data, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err == nil {
+			apiErr.Body = data
+		}
+		r.Body = io.NopCloser(bytes.NewReader(apiErr.Body))
+	}
+
+	var body kestraError
+	if len(apiErr.Body) > 0 && json.Unmarshal(apiErr.Body, &body) == nil {
+		apiErr.Message = body.Message
+		apiErr.Path = body.Links.Self.Href
+		apiErr.Violations = body.Embedded.Errors
+	} else if text := strings.TrimSpace(string(apiErr.Body)); text != "" && !strings.HasPrefix(text, "<") {
+		apiErr.Message = text
+	}
+
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(r.StatusCode)
+	}
+
+	return apiErr
+}
+
+// UnsupportedError is returned, before sending any request, by methods that the Kestra server
+// is known not to support. See Client.ServerInfo.
+type UnsupportedError struct {
+	// Feature is the API feature that is not supported.
+	Feature       string
+	ServerVersion string
+	// Requirement describes what the feature requires, e.g. "requires version 0.18.0 or later".
+	Requirement string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("kestra: %s not supported by server version %s: %s", e.Feature, e.ServerVersion, e.Requirement)
+}
+
+// Is makes errors.Is(err, ErrUnsupported) match any *UnsupportedError.
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupported
+}
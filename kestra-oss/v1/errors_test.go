@@ -0,0 +1,118 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   int
+		target error
+		want   bool
+	}{
+		{"400 is bad request", 400, ErrBadRequest, true},
+		{"401 is unauthorized", 401, ErrUnauthorized, true},
+		{"403 is forbidden", 403, ErrForbidden, true},
+		{"404 is not found", 404, ErrNotFound, true},
+		{"409 is conflict", 409, ErrConflict, true},
+		{"422 is unprocessable", 422, ErrUnprocessable, true},
+		{"503 is server error", 503, ErrServer, true},
+		{"404 is not conflict", 404, ErrConflict, false},
+		{"422 is not not found", 422, ErrNotFound, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error = fmt.Errorf("wrapped: %w", &APIError{StatusCode: tt.code})
+			if got := errors.Is(err, tt.target); got != tt.want {
+				t.Errorf("errors.Is() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckResponse_APIError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/flows", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"message":"Invalid entity: flow.id: must match \"^[a-zA-Z0-9][a-zA-Z0-9._-]*\"","_links":{"self":{"href":"/api/v1/flows","templated":false}},"_embedded":{"errors":[{"message":"flow.id: must match \"^[a-zA-Z0-9][a-zA-Z0-9._-]*\"","path":"flow.id"}]}}`)
+	})
+	testMux.HandleFunc("/api/v1/flows/tutorial/hello_world", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `Unauthorized`)
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		sentinel   error
+		message    string
+		violations []ConstraintViolation
+	}{
+		{"should decode constraint violations", "/api/v1/flows", ErrUnprocessable,
+			`Invalid entity: flow.id: must match "^[a-zA-Z0-9][a-zA-Z0-9._-]*"`,
+			[]ConstraintViolation{{`flow.id: must match "^[a-zA-Z0-9][a-zA-Z0-9._-]*"`, "flow.id"}},
+		},
+		{"should keep a plain text body as message", "/api/v1/flows/tutorial/hello_world", ErrUnauthorized,
+			"Unauthorized",
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := testClient.NewRequest(context.Background(), http.MethodGet, tt.path, nil, "")
+			resp, err := testClient.Do(req, nil)
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Do() error = %v, want *APIError", err)
+			}
+			if !errors.Is(err, tt.sentinel) {
+				t.Errorf("Do() error = %v, want %v", err, tt.sentinel)
+			}
+			if apiErr.Message != tt.message {
+				t.Errorf("Message got = %v, want %v", apiErr.Message, tt.message)
+			}
+			if !reflect.DeepEqual(apiErr.Violations, tt.violations) {
+				t.Errorf("Violations got = %v, want %v", apiErr.Violations, tt.violations)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != string(apiErr.Body) {
+				t.Errorf("Body got = %s, want %s", body, apiErr.Body)
+			}
+		})
+	}
+}
+
+func TestConstraintViolation_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want ConstraintViolation
+	}{
+		{"path as string", `{"message":"must not be null","path":"flow.namespace"}`, ConstraintViolation{"must not be null", "flow.namespace"}},
+		{"path as list", `{"message":"must not be null","path":["flow","tasks",0]}`, ConstraintViolation{"must not be null", "flow.tasks.0"}},
+		{"no path", `{"message":"Page Not Found"}`, ConstraintViolation{"Page Not Found", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got ConstraintViolation
+			if err := got.UnmarshalJSON([]byte(tt.data)); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnmarshalJSON() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,7 +3,8 @@ package v1
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"iter"
 	"net/http"
 	"net/url"
 	"strings"
@@ -49,8 +50,22 @@ type Execution struct {
 	TaskRunList  []ExecutionTaskRun `json:"taskRunList,omitempty" structs:"taskRunList,omitempty"`
 }
 
+// ExecutionSearchOptions specifies the filters, paging and sorting of ExecutionService.Search.
+type ExecutionSearchOptions struct {
+	// Query is a free-text search.
+	Query     string
+	Namespace string
+	FlowID    string
+	// State restricts the results to executions in one of these states (e.g. "SUCCESS", "FAILED").
+	State []string
+
+	ListOptions
+}
+
+// Get returns an execution by ID.
+// A missing execution is not an error: a nil execution is returned along with the 404 response.
 func (s *ExecutionService) Get(ctx context.Context, executionID string) (*Execution, *Response, error) {
-	apiEndpoint := fmt.Sprintf("/api/v1/executions/%s", executionID)
+	apiEndpoint := s.client.apiPath(ctx, "executions/%s", executionID)
 	req, err := s.client.NewRequest(ctx, http.MethodGet, apiEndpoint, nil, "")
 	if err != nil {
 		return nil, nil, err
@@ -58,12 +73,22 @@ func (s *ExecutionService) Get(ctx context.Context, executionID string) (*Execut
 
 	execution := new(Execution)
 	resp, err := s.client.Do(req, execution)
+	if errors.Is(err, ErrNotFound) {
+		return nil, resp, nil
+	}
+	if err != nil {
+		return nil, resp, err
+	}
 
 	return execution, resp, nil
 }
 
+// Create starts a new execution of a flow with the given inputs.
+// Any non-2xx response, including a missing flow (ErrNotFound), is returned as an *APIError.
+// As each call starts a new execution, Create is not retried by the client RetryPolicy unless
+// ctx comes from ContextWithRetry.
 func (s *ExecutionService) Create(ctx context.Context, namespace string, flowId string, input map[string]string) (*Execution, *Response, error) {
-	apiEndpoint := fmt.Sprintf("/api/v1/executions/%s/%s", namespace, flowId)
+	apiEndpoint := s.client.apiPath(ctx, "executions/%s/%s", namespace, flowId)
 
 	comb := []string{}
 
@@ -80,6 +105,63 @@ func (s *ExecutionService) Create(ctx context.Context, namespace string, flowId
 
 	execution := new(Execution)
 	resp, err := s.client.Do(req, execution)
+	if err != nil {
+		return nil, resp, err
+	}
 
 	return execution, resp, nil
 }
+
+// Search returns a page of the executions matching opts. opts may be nil to list every execution.
+// A 404 response is not an error: a nil result is returned along with the response.
+func (s *ExecutionService) Search(ctx context.Context, opts *ExecutionSearchOptions) (*PagedResults[Execution], *Response, error) {
+	params := url.Values{}
+	if opts != nil {
+		if opts.Query != "" {
+			params.Set("q", opts.Query)
+		}
+		if opts.Namespace != "" {
+			params.Set("namespace", opts.Namespace)
+		}
+		if opts.FlowID != "" {
+			params.Set("flowId", opts.FlowID)
+		}
+		for _, state := range opts.State {
+			params.Add("state", state)
+		}
+		opts.ListOptions.addValues(params)
+	}
+
+	apiEndpoint := s.client.apiPath(ctx, "executions/search") + "?" + params.Encode()
+	req, err := s.client.NewRequest(ctx, http.MethodGet, apiEndpoint, nil, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	searchResult := new(PagedResults[Execution])
+	resp, err := s.client.Do(req, searchResult)
+	if errors.Is(err, ErrNotFound) {
+		return nil, resp, nil
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return searchResult, resp, nil
+}
+
+// SearchAll returns an iterator over every execution matching opts, fetching the following
+// pages as needed. opts may be nil.
+func (s *ExecutionService) SearchAll(ctx context.Context, opts *ExecutionSearchOptions) iter.Seq2[Execution, error] {
+	search := ExecutionSearchOptions{}
+	if opts != nil {
+		search = *opts
+	}
+
+	// each page is fetched with a copy, so that search stays unchanged and the iterator can be reused
+	return paginate(&search.ListOptions, func(page *ListOptions) (*PagedResults[Execution], *Response, error) {
+		pageSearch := search
+		pageSearch.ListOptions = *page
+		return s.Search(ctx, &pageSearch)
+	})
+}
@@ -17,7 +17,7 @@ func TestExecutionService_Create(t *testing.T) {
 	}
 	tests := []struct {
 		name    string
-		s       ExecutionService
+		s       ExecutionAPI
 		args    args
 		want    *Response
 		wantErr bool
@@ -91,15 +91,22 @@ func TestExecutionService_Get(t *testing.T) {
 	}
 	tests := []struct {
 		name    string
-		s       ExecutionService
+		s       ExecutionAPI
 		args    args
 		want    *Execution
 		code    int
 		wantErr bool
-	}{}
+	}{
+		{"should not find anything", testClient.Execution,
+			args{context.Background(), "unknown"},
+			nil,
+			404,
+			false,
+		},
+	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, got1, err := tt.s.Get(tt.args.ctx, tt.args.executionID)
+			got, resp, err := tt.s.Get(tt.args.ctx, tt.args.executionID)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -107,9 +114,55 @@ func TestExecutionService_Get(t *testing.T) {
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Get() got = %v, want %v", got, tt.want)
 			}
-			if !reflect.DeepEqual(got1, tt.code) {
-				t.Errorf("Get() got1 = %v, want %v", got1, tt.code)
+			if !reflect.DeepEqual(resp.StatusCode, tt.code) {
+				t.Errorf("StatusCode got = %v, want %v", resp.StatusCode, tt.code)
 			}
 		})
 	}
 }
+
+func TestExecutionService_Search(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/executions/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+
+		params := r.URL.Query()
+		if params.Get("namespace") != "tutorial" || params.Get("flowId") != "hello_world" ||
+			!reflect.DeepEqual(params["state"], []string{"FAILED", "KILLED"}) {
+			t.Errorf("Request params: %v", params)
+		}
+
+		if params.Get("page") == "1" {
+			fmt.Fprint(w, `{"results":[{"id":"1","namespace":"tutorial","flowId":"hello_world"}],"total":2}`)
+		} else {
+			fmt.Fprint(w, `{"results":[{"id":"2","namespace":"tutorial","flowId":"hello_world"}],"total":2}`)
+		}
+	})
+
+	opts := &ExecutionSearchOptions{
+		Namespace:   "tutorial",
+		FlowID:      "hello_world",
+		State:       []string{"FAILED", "KILLED"},
+		ListOptions: ListOptions{Size: 1},
+	}
+
+	// the iterator starts over at the requested page each time it is ranged over
+	executions := testClient.Execution.SearchAll(context.Background(), opts)
+	for range 2 {
+		var got []string
+		for execution, err := range executions {
+			if err != nil {
+				t.Fatalf("SearchAll() error = %v", err)
+			}
+			got = append(got, execution.ID)
+		}
+		if want := []string{"1", "2"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("SearchAll() got = %v, want %v", got, want)
+		}
+	}
+	if opts.Page != 0 {
+		t.Errorf("SearchAll() modified the options: %v", opts)
+	}
+}
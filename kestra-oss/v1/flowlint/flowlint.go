@@ -0,0 +1,449 @@
+// Package flowlint lints the YAML source of Kestra flows without a Kestra server, e.g. in pre-commit hooks.
+//
+// Lint parses a source into the kestra.Flow model and reports the problems it finds, each with the line
+// and column of the offending YAML node:
+//
+//	flow, problems := flowlint.Lint(source)
+//	for _, p := range problems {
+//		fmt.Printf("%s:%s\n", filename, p)
+//	}
+package flowlint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	kestra "github.com/skeletonarmydev/go-kestra/kestra-oss/v1"
+	"github.com/skeletonarmydev/go-kestra/kestra-oss/v1/internal/ids"
+)
+
+// Severity is the severity of a problem.
+type Severity string
+
+const (
+	// Error is a problem that makes Kestra reject the flow or fail its executions.
+	Error Severity = "error"
+	// Warning is a likely mistake that does not prevent the flow from running.
+	Warning Severity = "warning"
+)
+
+// Rules reported by Lint.
+const (
+	RuleSyntax          = "syntax"
+	RuleFlowID          = "flow-id"
+	RuleNamespace       = "namespace"
+	RuleID              = "id"
+	RuleDuplicateID     = "duplicate-id"
+	RuleMissingType     = "missing-type"
+	RuleUndeclaredInput = "undeclared-input"
+	RuleUnusedInput     = "unused-input"
+	RuleUnknownOutput   = "unknown-output"
+	RuleInvalidType     = "invalid-type"
+	RuleDeprecatedName  = "deprecated-name"
+)
+
+// Problem is a problem found in a flow source.
+type Problem struct {
+	// Line and Column are the 1-based position of the YAML node the problem is about.
+	Line     int
+	Column   int
+	Severity Severity
+	// Rule is the name of the rule reporting the problem, e.g. RuleUnusedInput.
+	Rule    string
+	Message string
+}
+
+// String formats the problem as "line:column: severity: message (rule)".
+func (p Problem) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s (%s)", p.Line, p.Column, p.Severity, p.Message, p.Rule)
+}
+
+var (
+	// expressionPattern matches the Pebble expressions and tags of a string.
+	expressionPattern = regexp.MustCompile(`(?s)\{\{.*?\}\}|\{%.*?%\}`)
+	// inputsPattern and outputsPattern match the references to inputs and task outputs in an expression,
+	// either as inputs.name or as inputs['name'].
+	inputsPattern  = regexp.MustCompile(`(?:^|[^\w.])inputs(?:\.(\w+)|\[\s*['"]([^'"]+)['"]\s*\])`)
+	outputsPattern = regexp.MustCompile(`(?:^|[^\w.])outputs(?:\.(\w+)|\[\s*['"]([^'"]+)['"]\s*\])`)
+	// yamlErrorLine extracts the line of a YAML syntax error.
+	yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+)
+
+// taskContainerKeys are the properties of a task holding subtasks, as lists of tasks or, for cases,
+// as a map of lists of tasks.
+var taskContainerKeys = map[string]bool{
+	"tasks": true, "errors": true, "finally": true, "then": true, "else": true, "defaults": true, "cases": true,
+}
+
+// linter holds the state of a Lint call.
+type linter struct {
+	problems []Problem
+	// inputs are the declared inputs, and used the referenced ones.
+	inputs map[string]*yaml.Node
+	used   map[string]bool
+	// taskIDs are the IDs of the tasks seen so far, in flow order.
+	taskIDs map[string]bool
+}
+
+// Lint parses the YAML source of a flow and returns it with the problems found, sorted by position.
+// If the source cannot be parsed, the returned flow is nil and the only problem is the syntax error.
+// If a value does not fit the kestra.Flow model, e.g. an expression where a number is expected, the
+// returned flow is nil as well, the value is reported with RuleInvalidType and the other rules still run.
+func Lint(source string) (*kestra.Flow, []Problem) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(source), &doc); err != nil {
+		return nil, []Problem{syntaxProblem(err)}
+	}
+	flow, err := kestra.ParseFlowYAML(source)
+	if err != nil && (doc.Kind == 0 || doc.Content[0].Kind != yaml.MappingNode) {
+		return nil, []Problem{syntaxProblem(err)}
+	}
+
+	l := &linter{
+		inputs:  make(map[string]*yaml.Node),
+		used:    make(map[string]bool),
+		taskIDs: make(map[string]bool),
+	}
+	if err != nil {
+		l.reportModelError(doc.Content[0], err)
+	}
+	l.lintFlow(doc.Content[0])
+
+	sort.SliceStable(l.problems, func(i, j int) bool {
+		a, b := l.problems[i], l.problems[j]
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	return flow, l.problems
+}
+
+// syntaxProblem returns the problem of a source that cannot be parsed, at the line given by err if any.
+func syntaxProblem(err error) Problem {
+	p := Problem{Line: 1, Column: 1, Severity: Error, Rule: RuleSyntax, Message: err.Error()}
+	if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		p.Line, _ = strconv.Atoi(m[1])
+	}
+	return p
+}
+
+// jsonKinds describes the kinds of JSON values named by json.UnmarshalTypeError.
+var jsonKinds = map[string]string{
+	"string": "a string", "number": "a number", "bool": "a boolean", "object": "a mapping", "array": "a list",
+}
+
+// reportModelError reports the error of decoding the flow into the kestra.Flow model. A value of the wrong
+// type is reported at its node, found by the name of its property; other errors are reported at root.
+func (l *linter) reportModelError(root *yaml.Node, err error) {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Field == "" {
+		l.report(root, Error, RuleInvalidType, "%s", err)
+		return
+	}
+
+	// the field is only the last property of the path, as the model decodes its parts separately
+	key := typeErr.Field[strings.LastIndex(typeErr.Field, ".")+1:]
+	n := findValue(root, key, typeErr.Value)
+	if n == nil {
+		n = root
+	}
+	l.report(n, Error, RuleInvalidType, "%s must be %s, not %s", key, kindOf(typeErr.Type), jsonKinds[typeErr.Value])
+}
+
+// findValue returns the first value of key, in document order, that is a JSON value of the given kind.
+func findValue(n *yaml.Node, key string, kind string) *yaml.Node {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Value == key && jsonKind(n.Content[i+1]) == kind {
+				return n.Content[i+1]
+			}
+			if found := findValue(n.Content[i+1], key, kind); found != nil {
+				return found
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			if found := findValue(item, key, kind); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// jsonKind returns the kind of JSON value n decodes to, as named by json.UnmarshalTypeError.
+func jsonKind(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	case yaml.AliasNode:
+		return jsonKind(n.Alias)
+	}
+	switch n.ShortTag() {
+	case "!!int", "!!float":
+		return "number"
+	case "!!bool":
+		return "bool"
+	case "!!null":
+		return "null"
+	}
+	return "string"
+}
+
+// kindOf describes the values of type t.
+func kindOf(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return jsonKinds["bool"]
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return jsonKinds["number"]
+	case reflect.String:
+		return jsonKinds["string"]
+	case reflect.Slice, reflect.Array:
+		return jsonKinds["array"]
+	case reflect.Map, reflect.Struct:
+		return jsonKinds["object"]
+	}
+	return t.String()
+}
+
+func (l *linter) report(n *yaml.Node, severity Severity, rule string, format string, args ...interface{}) {
+	l.problems = append(l.problems, Problem{
+		Line:     n.Line,
+		Column:   n.Column,
+		Severity: severity,
+		Rule:     rule,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *linter) lintFlow(root *yaml.Node) {
+	if id := value(root, "id"); id == nil {
+		l.report(root, Error, RuleFlowID, "flow id is required")
+	} else {
+		l.checkID(id, RuleFlowID, "flow id", ids.Flow)
+	}
+	if ns := value(root, "namespace"); ns == nil {
+		l.report(root, Error, RuleNamespace, "namespace is required")
+	} else {
+		l.checkID(ns, RuleNamespace, "namespace", ids.Namespace)
+	}
+
+	if inputs := value(root, "inputs"); inputs != nil && inputs.Kind == yaml.SequenceNode {
+		for _, input := range inputs.Content {
+			l.lintInput(input)
+		}
+	}
+
+	// the main tasks run first, so the error and finally tasks can use their outputs
+	// whatever the order of the keys in the source
+	for _, key := range []string{"tasks", "errors", "finally"} {
+		if tasks := value(root, key); tasks != nil {
+			l.lintTasks(tasks)
+		}
+	}
+
+	triggerIDs := make(map[string]bool)
+	if triggers := value(root, "triggers"); triggers != nil && triggers.Kind == yaml.SequenceNode {
+		for _, trigger := range triggers.Content {
+			l.lintID(trigger, "trigger", triggerIDs)
+			l.checkReferences(trigger, false)
+		}
+	}
+
+	// every other property may use inputs, and the flow outputs may use the outputs of any task
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		switch key := root.Content[i].Value; key {
+		case "tasks", "errors", "finally", "triggers":
+		default:
+			l.checkReferences(root.Content[i+1], key == "outputs")
+		}
+	}
+
+	names := make([]string, 0, len(l.inputs))
+	for name := range l.inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !l.used[name] {
+			l.report(l.inputs[name], Warning, RuleUnusedInput, "input %q is declared but never used", name)
+		}
+	}
+}
+
+func (l *linter) lintInput(input *yaml.Node) {
+	if input.Kind != yaml.MappingNode {
+		return
+	}
+	id := value(input, "id")
+	if id == nil {
+		// inputs were identified by their name before Kestra 0.15
+		if id = value(input, "name"); id != nil {
+			l.report(id, Warning, RuleDeprecatedName, "input name is deprecated, use id")
+		}
+	}
+	switch {
+	case id == nil:
+		l.report(input, Error, RuleID, "input id is required")
+	case !l.checkID(id, RuleID, "input id", ids.Input):
+	case l.inputs[id.Value] != nil:
+		l.report(id, Error, RuleDuplicateID, "duplicate input id %q", id.Value)
+	default:
+		l.inputs[id.Value] = id
+	}
+	if value(input, "type") == nil {
+		l.report(input, Error, RuleMissingType, "input type is required")
+	}
+}
+
+// checkID checks that the namespace or ID n matches pattern and is not too long, reporting it as what
+// otherwise, and reports whether it is valid.
+func (l *linter) checkID(n *yaml.Node, rule string, what string, pattern *regexp.Regexp) bool {
+	switch {
+	case !pattern.MatchString(n.Value):
+		l.report(n, Error, rule, "%s %q must match %s", what, n.Value, pattern)
+	case len(n.Value) > ids.MaxLength:
+		l.report(n, Error, rule, "%s %q must be at most %d characters", what, n.Value, ids.MaxLength)
+	default:
+		return true
+	}
+	return false
+}
+
+// lintTasks lints a list of tasks and their subtasks, in flow order.
+func (l *linter) lintTasks(tasks *yaml.Node) {
+	if tasks.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, task := range tasks.Content {
+		if task.Kind != yaml.MappingNode {
+			continue
+		}
+		l.checkReferences(task, true)
+		// the task is available to the following tasks, including its own subtasks
+		l.lintID(task, "task", l.taskIDs)
+
+		for i := 0; i+1 < len(task.Content); i += 2 {
+			if !taskContainerKeys[task.Content[i].Value] || !holdsTasks(task.Content[i+1]) {
+				continue
+			}
+			children := task.Content[i+1]
+			if children.Kind == yaml.MappingNode { // cases
+				for j := 1; j < len(children.Content); j += 2 {
+					l.lintTasks(children.Content[j])
+				}
+				continue
+			}
+			l.lintTasks(children)
+		}
+	}
+}
+
+// lintID checks the ID and the type of a task or a trigger, and that the ID is not in seen.
+func (l *linter) lintID(n *yaml.Node, kind string, seen map[string]bool) {
+	if n.Kind != yaml.MappingNode {
+		return
+	}
+	id := value(n, "id")
+	switch {
+	case id == nil:
+		l.report(n, Error, RuleID, "%s id is required", kind)
+	case !l.checkID(id, RuleID, kind+" id", ids.Task):
+	case seen[id.Value]:
+		l.report(id, Error, RuleDuplicateID, "duplicate %s id %q", kind, id.Value)
+	}
+	if id != nil {
+		seen[id.Value] = true
+	}
+	if value(n, "type") == nil {
+		l.report(n, Error, RuleMissingType, "%s type is required", kind)
+	}
+}
+
+// checkReferences checks the references to inputs in the expressions of n, and with outputs set, the
+// references to the outputs of tasks. The subtasks of a task are left out, as they are checked in turn.
+func (l *linter) checkReferences(n *yaml.Node, outputs bool) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if isTask(n) && taskContainerKeys[n.Content[i].Value] && holdsTasks(n.Content[i+1]) {
+				continue
+			}
+			l.checkReferences(n.Content[i+1], outputs)
+		}
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			l.checkReferences(item, outputs)
+		}
+	case yaml.AliasNode:
+		l.checkReferences(n.Alias, outputs)
+	case yaml.ScalarNode:
+		for _, expression := range expressionPattern.FindAllString(n.Value, -1) {
+			for _, m := range inputsPattern.FindAllStringSubmatch(expression, -1) {
+				name := m[1] + m[2]
+				l.used[name] = true
+				if l.inputs[name] == nil {
+					l.report(n, Error, RuleUndeclaredInput, "input %q is used but not declared", name)
+				}
+			}
+			if !outputs {
+				continue
+			}
+			for _, m := range outputsPattern.FindAllStringSubmatch(expression, -1) {
+				if id := m[1] + m[2]; !l.taskIDs[id] {
+					l.report(n, Error, RuleUnknownOutput, "outputs of task %q are used before the task is defined", id)
+				}
+			}
+		}
+	}
+}
+
+// isTask reports whether n looks like a task or a trigger: a mapping with an id or a type.
+func isTask(n *yaml.Node) bool {
+	return value(n, "id") != nil || value(n, "type") != nil
+}
+
+// holdsTasks reports whether n is a list of tasks, or a map of lists of tasks.
+func holdsTasks(n *yaml.Node) bool {
+	switch n.Kind {
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			if item.Kind != yaml.MappingNode || !isTask(item) {
+				return false
+			}
+		}
+		return true
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			if !holdsTasks(n.Content[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// value returns the value of key in the mapping node n, or nil.
+func value(n *yaml.Node, key string) *yaml.Node {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
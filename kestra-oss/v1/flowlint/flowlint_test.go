@@ -0,0 +1,170 @@
+package flowlint
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{"valid flow", `id: hello_world
+namespace: company.team
+inputs:
+  - id: name
+    type: STRING
+  - id: my-input
+    type: STRING
+tasks:
+  - id: fetch
+    type: io.kestra.plugin.core.http.Request
+    uri: "https://example.com/{{ inputs.name }}"
+  - id: each
+    type: io.kestra.plugin.core.flow.ForEach
+    values: "{{ outputs.fetch.body }}"
+    tasks:
+      - id: log
+        type: io.kestra.plugin.core.log.Log
+        message: "{{ inputs['my-input'] }} {{ outputs.each.value }}"
+errors:
+  - id: alert
+    type: io.kestra.plugin.core.log.Log
+    message: "{{ outputs.fetch.code }}"
+`, nil},
+		{"invalid IDs", `id: hello world
+namespace: Company
+tasks:
+  - id: my.task
+    type: io.kestra.plugin.core.log.Log
+  - type: io.kestra.plugin.core.log.Log
+triggers:
+  - id: daily
+`, []string{
+			`1:5: error: flow id "hello world" must match ^[a-zA-Z0-9][a-zA-Z0-9._-]*$ (flow-id)`,
+			`2:12: error: namespace "Company" must match ^[a-z0-9][a-z0-9._-]*$ (namespace)`,
+			`4:9: error: task id "my.task" must match ^[a-zA-Z0-9][a-zA-Z0-9_-]*$ (id)`,
+			`6:5: error: task id is required (id)`,
+			`8:5: error: trigger type is required (missing-type)`,
+		}},
+		{"duplicate nested task IDs", `id: hello_world
+namespace: company.team
+tasks:
+  - id: log
+    type: io.kestra.plugin.core.log.Log
+  - id: switch
+    type: io.kestra.plugin.core.flow.Switch
+    value: a
+    cases:
+      a:
+        - id: log
+          type: io.kestra.plugin.core.log.Log
+finally:
+  - id: switch
+    type: io.kestra.plugin.core.log.Log
+`, []string{
+			`11:15: error: duplicate task id "log" (duplicate-id)`,
+			`14:9: error: duplicate task id "switch" (duplicate-id)`,
+		}},
+		{"inputs", `id: hello_world
+namespace: company.team
+inputs:
+  - id: unused
+    type: STRING
+  - id: untyped
+tasks:
+  - id: log
+    type: io.kestra.plugin.core.log.Log
+    message: |
+      Hello {{ inputs.untyped }}
+      {{ inputs.missing }}, not inputs.plain
+`, []string{
+			`4:9: warning: input "unused" is declared but never used (unused-input)`,
+			`6:5: error: input type is required (missing-type)`,
+			`10:14: error: input "missing" is used but not declared (undeclared-input)`,
+		}},
+		{"outputs used too early", `id: hello_world
+namespace: company.team
+tasks:
+  - id: first
+    type: io.kestra.plugin.core.log.Log
+    message: "{{ outputs.second.value }} {{ outputs.first.value }}"
+  - id: second
+    type: io.kestra.plugin.core.log.Log
+    message: "{{ outputs.missing.value }}"
+`, []string{
+			`6:14: error: outputs of task "second" are used before the task is defined (unknown-output)`,
+			`6:14: error: outputs of task "first" are used before the task is defined (unknown-output)`,
+			`9:14: error: outputs of task "missing" are used before the task is defined (unknown-output)`,
+		}},
+		{"too long IDs", "id: " + strings.Repeat("f", 101) + `
+namespace: ` + strings.Repeat("n", 101) + `
+inputs:
+  - id: ` + strings.Repeat("i", 101) + `
+    type: STRING
+tasks:
+  - id: ` + strings.Repeat("t", 101) + `
+    type: io.kestra.plugin.core.log.Log
+`, []string{
+			`1:5: error: flow id "` + strings.Repeat("f", 101) + `" must be at most 100 characters (flow-id)`,
+			`2:12: error: namespace "` + strings.Repeat("n", 101) + `" must be at most 100 characters (namespace)`,
+			`4:9: error: input id "` + strings.Repeat("i", 101) + `" must be at most 100 characters (id)`,
+			`7:9: error: task id "` + strings.Repeat("t", 101) + `" must be at most 100 characters (id)`,
+		}},
+		{"legacy input names", `id: hello_world
+namespace: company.team
+inputs:
+  - name: user
+    type: STRING
+tasks:
+  - id: log
+    type: io.kestra.plugin.core.log.Log
+    message: "{{ inputs.user }}"
+`, []string{
+			`4:11: warning: input name is deprecated, use id (deprecated-name)`,
+		}},
+		{"invalid types", `id: hello_world
+namespace: company.team
+retry:
+  type: constant
+  interval: PT1M
+  maxAttempt: "{{ inputs.n }}"
+tasks:
+  - id: log
+    type: io.kestra.plugin.core.log.Log
+    message: "{{ inputs.missing }}"
+`, []string{
+			`6:15: error: maxAttempt must be a number, not a string (invalid-type)`,
+			`6:15: error: input "n" is used but not declared (undeclared-input)`,
+			`10:14: error: input "missing" is used but not declared (undeclared-input)`,
+		}},
+		{"invalid type without a property name", "id: hello_world\nnamespace: company.team\nlabels: 3\n", []string{
+			"1:1: error: json: cannot unmarshal number into Go value of type []v1.Label (invalid-type)",
+		}},
+		{"syntax error", "id: hello_world\nnamespace: [company\n", []string{
+			"1:1: error: yaml: line 1: did not find expected ',' or ']' (syntax)",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flow, problems := Lint(tt.source)
+			var got []string
+			for _, p := range problems {
+				got = append(got, p.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() got = %q, want %q", got, tt.want)
+			}
+			invalid := false
+			for _, p := range problems {
+				invalid = invalid || p.Rule == RuleSyntax || p.Rule == RuleInvalidType
+			}
+			if (flow == nil) != invalid {
+				t.Errorf("Lint() flow = %v, want a flow unless the source cannot be decoded", flow)
+			}
+		})
+	}
+}
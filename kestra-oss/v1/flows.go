@@ -1,48 +1,385 @@
 package v1
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
 	"net/http"
+	"net/url"
+	"sort"
 )
 
 type FlowService service
 
+// FlowTask is a task of a flow, or one of its error or finally handlers.
+// The common task properties are typed fields; the properties specific to the task type (e.g. "message",
+// "uri" or "script") are kept in Properties, so that re-encoding a decoded task does not lose any of them.
 type FlowTask struct {
-	ID          string     `json:"id,omitempty" structs:"id,omitempty"`
-	Type        string     `json:"type,omitempty" structs:"type,omitempty"`
-	Description string     `json:"description,omitempty" structs:"description,omitempty"`
-	Tasks       []FlowTask `json:"tasks,omitempty" structs:"tasks,omitempty"`
+	ID           string     `json:"id,omitempty" structs:"id,omitempty"`
+	Type         string     `json:"type,omitempty" structs:"type,omitempty"`
+	Description  string     `json:"description,omitempty" structs:"description,omitempty"`
+	Disabled     bool       `json:"disabled,omitempty" structs:"disabled,omitempty"`
+	AllowFailure bool       `json:"allowFailure,omitempty" structs:"allowFailure,omitempty"`
+	Timeout      string     `json:"timeout,omitempty" structs:"timeout,omitempty"`
+	Retry        *Retry     `json:"retry,omitempty" structs:"retry,omitempty"`
+	Tasks        []FlowTask `json:"tasks,omitempty" structs:"tasks,omitempty"`
+	Errors       []FlowTask `json:"errors,omitempty" structs:"errors,omitempty"`
+	Finally      []FlowTask `json:"finally,omitempty" structs:"finally,omitempty"`
+
+	// Properties holds every other property of the task. Numbers are decoded as json.Number.
+	Properties map[string]interface{} `json:"-" structs:"-"`
+	// Raw is the JSON the task was decoded from. It is not used when encoding the task.
+	Raw json.RawMessage `json:"-" structs:"-"`
+}
+
+type flowTask FlowTask
+
+// UnmarshalJSON decodes a task, keeping its untyped properties and its raw JSON.
+func (t *FlowTask) UnmarshalJSON(data []byte) error {
+	*t = FlowTask{}
+	properties, err := unmarshalWithExtra(data, (*flowTask)(t))
+	if err != nil {
+		return err
+	}
+	t.Properties = properties
+	t.Raw = append(json.RawMessage(nil), data...)
+	return nil
 }
 
-type FlowInput struct {
+// MarshalJSON encodes a task with its typed fields and its Properties.
+func (t FlowTask) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(flowTask(t), t.Properties)
+}
+
+// FlowTrigger is a trigger of a flow. As for FlowTask, the properties specific to the
+// trigger type are kept in Properties.
+type FlowTrigger struct {
 	ID          string `json:"id,omitempty" structs:"id,omitempty"`
 	Type        string `json:"type,omitempty" structs:"type,omitempty"`
-	Name        string `json:"name,omitempty" structs:"name,omitempty"`
 	Description string `json:"description,omitempty" structs:"description,omitempty"`
-	Defaults    string `json:"defaults,omitempty" structs:"defaults,omitempty"`
-	Required    bool   `json:"required,omitempty" structs:"required,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty" structs:"disabled,omitempty"`
+
+	// Properties holds every other property of the trigger. Numbers are decoded as json.Number.
+	Properties map[string]interface{} `json:"-" structs:"-"`
+	// Raw is the JSON the trigger was decoded from. It is not used when encoding the trigger.
+	Raw json.RawMessage `json:"-" structs:"-"`
+}
+
+type flowTrigger FlowTrigger
+
+// UnmarshalJSON decodes a trigger, keeping its untyped properties and its raw JSON.
+func (t *FlowTrigger) UnmarshalJSON(data []byte) error {
+	*t = FlowTrigger{}
+	properties, err := unmarshalWithExtra(data, (*flowTrigger)(t))
+	if err != nil {
+		return err
+	}
+	t.Properties = properties
+	t.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON encodes a trigger with its typed fields and its Properties.
+func (t FlowTrigger) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(flowTrigger(t), t.Properties)
+}
+
+// Label is a key/value label of a flow.
+type Label struct {
+	Key   string `json:"key" structs:"key"`
+	Value string `json:"value" structs:"value"`
+}
+
+// Labels are the labels of a flow. They decode from both the list form used by the API
+// and the map form allowed in flow sources, and always encode to the list form.
+type Labels []Label
+
+// UnmarshalJSON decodes labels from a list of key/value objects or from a map.
+func (l *Labels) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var m map[string]interface{}
+		if err := decodeJSON(data, &m); err != nil {
+			return err
+		}
+		labels := make(Labels, 0, len(m))
+		for key, value := range m {
+			labels = append(labels, Label{Key: key, Value: fmt.Sprint(value)})
+		}
+		sort.Slice(labels, func(i, j int) bool { return labels[i].Key < labels[j].Key })
+		*l = labels
+		return nil
+	}
+
+	var labels []Label
+	if err := json.Unmarshal(data, &labels); err != nil {
+		return err
+	}
+	*l = labels
+	return nil
+}
+
+// Get returns the value of the label key.
+func (l Labels) Get(key string) (string, bool) {
+	for _, label := range l {
+		if label.Key == key {
+			return label.Value, true
+		}
+	}
+	return "", false
+}
+
+// Retry is the retry policy of a flow or a task.
+type Retry struct {
+	// Type is "constant", "exponential" or "random".
+	Type string `json:"type,omitempty" structs:"type,omitempty"`
+	// Behavior is "RETRY_FAILED_TASK" or "CREATE_NEW_EXECUTION".
+	Behavior       string      `json:"behavior,omitempty" structs:"behavior,omitempty"`
+	MaxAttempt     int         `json:"maxAttempt,omitempty" structs:"maxAttempt,omitempty"`
+	MaxDuration    string      `json:"maxDuration,omitempty" structs:"maxDuration,omitempty"`
+	Interval       string      `json:"interval,omitempty" structs:"interval,omitempty"`
+	MinInterval    string      `json:"minInterval,omitempty" structs:"minInterval,omitempty"`
+	MaxInterval    string      `json:"maxInterval,omitempty" structs:"maxInterval,omitempty"`
+	DelayFactor    json.Number `json:"delayFactor,omitempty" structs:"delayFactor,omitempty"`
+	WarningOnRetry bool        `json:"warningOnRetry,omitempty" structs:"warningOnRetry,omitempty"`
+
+	// Extra holds the properties not modeled by Retry.
+	Extra map[string]interface{} `json:"-" structs:"-"`
+}
+
+type retry Retry
+
+// UnmarshalJSON decodes a retry policy, keeping its unknown properties in Extra.
+func (r *Retry) UnmarshalJSON(data []byte) error {
+	*r = Retry{}
+	extra, err := unmarshalWithExtra(data, (*retry)(r))
+	r.Extra = extra
+	return err
+}
+
+// MarshalJSON encodes a retry policy with its Extra properties.
+func (r Retry) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(retry(r), r.Extra)
+}
+
+// Concurrency limits the number of concurrent executions of a flow.
+type Concurrency struct {
+	Limit int `json:"limit,omitempty" structs:"limit,omitempty"`
+	// Behavior is "QUEUE", "CANCEL" or "FAIL".
+	Behavior string `json:"behavior,omitempty" structs:"behavior,omitempty"`
+
+	// Extra holds the properties not modeled by Concurrency.
+	Extra map[string]interface{} `json:"-" structs:"-"`
+}
+
+type concurrency Concurrency
+
+// UnmarshalJSON decodes a concurrency limit, keeping its unknown properties in Extra.
+func (c *Concurrency) UnmarshalJSON(data []byte) error {
+	*c = Concurrency{}
+	extra, err := unmarshalWithExtra(data, (*concurrency)(c))
+	c.Extra = extra
+	return err
 }
 
-// Flow struct
+// MarshalJSON encodes a concurrency limit with its Extra properties.
+func (c Concurrency) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(concurrency(c), c.Extra)
+}
+
+// PluginDefault sets default property values for every task or trigger of a given type.
+type PluginDefault struct {
+	Type   string                 `json:"type,omitempty" structs:"type,omitempty"`
+	Forced bool                   `json:"forced,omitempty" structs:"forced,omitempty"`
+	Values map[string]interface{} `json:"values,omitempty" structs:"values,omitempty"`
+
+	// Extra holds the properties not modeled by PluginDefault.
+	Extra map[string]interface{} `json:"-" structs:"-"`
+}
+
+type pluginDefault PluginDefault
+
+// UnmarshalJSON decodes plugin defaults, keeping their unknown properties in Extra.
+func (d *PluginDefault) UnmarshalJSON(data []byte) error {
+	*d = PluginDefault{}
+	extra, err := unmarshalWithExtra(data, (*pluginDefault)(d))
+	d.Extra = extra
+	return err
+}
+
+// MarshalJSON encodes plugin defaults with their Extra properties.
+func (d PluginDefault) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(pluginDefault(d), d.Extra)
+}
+
+// Flow is a Kestra flow.
+// The properties of the flow that are not modeled by its fields are kept in Extra, so that
+// decoding then encoding a flow produces an equivalent flow.
 type Flow struct {
-	ID          string      `json:"id,omitempty" structs:"id,omitempty"`
-	Namespace   string      `json:"namespace,omitempty" structs:"namespace,omitempty"`
-	Revision    json.Number `json:"revision,omitempty" structs:"revision,omitempty"`
-	Description string      `json:"description,omitempty" structs:"description,omitempty"`
-	Tasks       []FlowTask  `json:"tasks,omitempty" structs:"tasks,omitempty"`
-	Inputs      []FlowInput `json:"inputs,omitempty" structs:"inputs,omitempty"`
-	Source      string      `json:"source,omitempty" structs:"source,omitempty"`
+	ID             string                 `json:"id,omitempty" structs:"id,omitempty"`
+	Namespace      string                 `json:"namespace,omitempty" structs:"namespace,omitempty"`
+	Revision       json.Number            `json:"revision,omitempty" structs:"revision,omitempty"`
+	Description    string                 `json:"description,omitempty" structs:"description,omitempty"`
+	Labels         Labels                 `json:"labels,omitempty" structs:"labels,omitempty"`
+	Inputs         []FlowInput            `json:"inputs,omitempty" structs:"inputs,omitempty"`
+	Variables      map[string]interface{} `json:"variables,omitempty" structs:"variables,omitempty"`
+	Tasks          []FlowTask             `json:"tasks,omitempty" structs:"tasks,omitempty"`
+	Errors         []FlowTask             `json:"errors,omitempty" structs:"errors,omitempty"`
+	Finally        []FlowTask             `json:"finally,omitempty" structs:"finally,omitempty"`
+	Triggers       []FlowTrigger          `json:"triggers,omitempty" structs:"triggers,omitempty"`
+	PluginDefaults []PluginDefault        `json:"pluginDefaults,omitempty" structs:"pluginDefaults,omitempty"`
+	Concurrency    *Concurrency           `json:"concurrency,omitempty" structs:"concurrency,omitempty"`
+	Retry          *Retry                 `json:"retry,omitempty" structs:"retry,omitempty"`
+	Disabled       bool                   `json:"disabled,omitempty" structs:"disabled,omitempty"`
+	Deleted        bool                   `json:"deleted,omitempty" structs:"deleted,omitempty"`
+	Source         string                 `json:"source,omitempty" structs:"source,omitempty"`
+
+	// Extra holds the properties not modeled by Flow. Numbers are decoded as json.Number.
+	Extra map[string]interface{} `json:"-" structs:"-"`
+}
+
+type flow Flow
+
+// UnmarshalJSON decodes a flow, keeping its unknown properties in Extra.
+func (f *Flow) UnmarshalJSON(data []byte) error {
+	*f = Flow{}
+	extra, err := unmarshalWithExtra(data, (*flow)(f))
+	f.Extra = extra
+	return err
+}
+
+// MarshalJSON encodes a flow with its Extra properties.
+func (f Flow) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(flow(f), f.Extra)
+}
+
+// FlowRef identifies a flow by namespace and ID.
+type FlowRef struct {
+	Namespace string `json:"namespace" structs:"namespace"`
+	ID        string `json:"id" structs:"id"`
+}
+
+// FlowFilter selects flows by free-text query, namespace and labels.
+type FlowFilter struct {
+	// Query is a free-text search.
+	Query string
+	// Namespace restricts the flows to this namespace and its sub-namespaces.
+	Namespace string
+	// Labels restricts the flows to those having every one of these labels.
+	Labels map[string]string
+}
+
+// FlowSearchOptions specifies the filters, paging and sorting of FlowService.Search, e.g. the flows
+// of company.data and its sub-namespaces labelled team=payments, sorted by ID:
+//
+//	&kestra.FlowSearchOptions{
+//		FlowFilter:  kestra.FlowFilter{Namespace: "company.data", Labels: map[string]string{"team": "payments"}},
+//		ListOptions: kestra.ListOptions{Sort: []string{kestra.SortBy("id", kestra.SortAsc)}},
+//	}
+type FlowSearchOptions struct {
+	FlowFilter
+	ListOptions
+}
+
+// isZero reports whether the filter selects every flow.
+func (f *FlowFilter) isZero() bool {
+	return f == nil || f.Query == "" && f.Namespace == "" && len(f.Labels) == 0
+}
+
+// addValues adds the filter parameters to query. Labels are sent as "key:value", sorted by key.
+func (f *FlowFilter) addValues(query url.Values) {
+	if f == nil {
+		return
+	}
+	if f.Query != "" {
+		query.Set("q", f.Query)
+	}
+	if f.Namespace != "" {
+		query.Set("namespace", f.Namespace)
+	}
+	keys := make([]string, 0, len(f.Labels))
+	for key := range f.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		query.Add("labels", key+":"+f.Labels[key])
+	}
+}
+
+// FlowStatus is the outcome of a bulk operation for one flow.
+type FlowStatus string
+
+const (
+	// FlowDeleted means the flow was deleted.
+	FlowDeleted FlowStatus = "DELETED"
+	// FlowEnabled means the flow was disabled and has been enabled.
+	FlowEnabled FlowStatus = "ENABLED"
+	// FlowDisabled means the flow was enabled and has been disabled.
+	FlowDisabled FlowStatus = "DISABLED"
+	// FlowUnchanged means the flow was already in the requested state.
+	FlowUnchanged FlowStatus = "UNCHANGED"
+	// FlowNotFound means the flow does not exist, so the operation did not apply to it.
+	FlowNotFound FlowStatus = "NOT_FOUND"
+	// FlowUnknown means the server applied the operation to only some of the flows, without telling which.
+	FlowUnknown FlowStatus = "UNKNOWN"
+)
+
+// FlowOutcome is the outcome of a bulk operation for one flow.
+type FlowOutcome struct {
+	FlowRef
+	Status FlowStatus
+}
+
+// applied reports whether the operation applied to the flow.
+func (o FlowOutcome) applied() bool {
+	return o.Status == FlowDeleted || o.Status == FlowEnabled || o.Status == FlowDisabled
+}
+
+// settleOutcomes makes the expected outcomes of a bulk operation agree with the number of flows the server
+// reports as affected. If the server affected none of the flows expected to change, they get the skipped
+// status. If it affected some of them only, they get the FlowUnknown status, as the server does not tell
+// which ones. Flows affected on top of the expected ones, e.g. created after a search, are not reported.
+func settleOutcomes(outcomes []FlowOutcome, count int, skipped FlowStatus) {
+	expected := 0
+	for _, outcome := range outcomes {
+		if outcome.applied() {
+			expected++
+		}
+	}
+	if count >= expected {
+		return
+	}
+	for i := range outcomes {
+		if !outcomes[i].applied() {
+			continue
+		}
+		if count == 0 {
+			outcomes[i].Status = skipped
+		} else {
+			outcomes[i].Status = FlowUnknown
+		}
+	}
 }
 
-type SearchResult struct {
-	Results []Flow `json:"results,omitempty" structs:"results,omitempty"`
-	Total   int32  `json:"total,omitempty" structs:"total,omitempty"`
+// BulkResult is the result of a bulk operation on flows.
+type BulkResult struct {
+	// Count is the number of flows the server reports as affected.
+	Count int `json:"count" structs:"count"`
+	// Outcomes are the outcomes for each flow, in the order of the request (or of the search for
+	// bulk operations by query).
+	Outcomes []FlowOutcome `json:"-" structs:"-"`
 }
 
+// SearchResult is a page of flows returned by Search.
+type SearchResult = PagedResults[Flow]
+
+// GetAll returns every flow of the given namespace.
+// An unknown namespace is not an error: a nil slice is returned along with the 404 response.
 func (s *FlowService) GetAll(ctx context.Context, namespace string) (*[]Flow, *Response, error) {
-	apiEndpoint := fmt.Sprintf("/api/v1/flows/%s", namespace)
+	apiEndpoint := s.client.apiPath(ctx, "flows/%s", namespace)
 	req, err := s.client.NewRequest(ctx, http.MethodGet, apiEndpoint, nil, "")
 	if err != nil {
 		return nil, nil, err
@@ -50,15 +387,20 @@ func (s *FlowService) GetAll(ctx context.Context, namespace string) (*[]Flow, *R
 
 	flow := new([]Flow)
 	resp, err := s.client.Do(req, flow)
-	if resp.StatusCode == 404 {
+	if errors.Is(err, ErrNotFound) {
 		return nil, resp, nil
 	}
+	if err != nil {
+		return nil, resp, err
+	}
 
 	return flow, resp, nil
 }
 
+// Get returns a flow by namespace and ID.
+// A missing flow is not an error: a nil flow is returned along with the 404 response.
 func (s *FlowService) Get(ctx context.Context, namespace string, flowID string) (*Flow, *Response, error) {
-	apiEndpoint := fmt.Sprintf("/api/v1/flows/%s/%s", namespace, flowID)
+	apiEndpoint := s.client.apiPath(ctx, "flows/%s/%s", namespace, flowID)
 	req, err := s.client.NewRequest(ctx, http.MethodGet, apiEndpoint, nil, "")
 	if err != nil {
 		return nil, nil, err
@@ -66,31 +408,57 @@ func (s *FlowService) Get(ctx context.Context, namespace string, flowID string)
 
 	flow := new(Flow)
 	resp, err := s.client.Do(req, flow)
-	if resp.StatusCode == 404 {
+	if errors.Is(err, ErrNotFound) {
 		return nil, resp, nil
 	}
+	if err != nil {
+		return nil, resp, err
+	}
 
 	return flow, resp, nil
 }
 
+// GetSource returns the YAML source of a flow.
+// A missing flow is not an error: an empty source is returned along with the 404 response.
 func (s *FlowService) GetSource(ctx context.Context, namespace string, flowID string) (string, *Response, error) {
-	apiEndpoint := fmt.Sprintf("/api/v1/flows/%s/%s?source=true", namespace, flowID)
+	flow, resp, err := s.getWithSource(ctx, namespace, flowID)
+	if flow == nil || err != nil {
+		return "", resp, err
+	}
+
+	return flow.Source, resp, nil
+}
+
+// getWithSource returns the latest revision of a flow along with its source, or nil if it is missing.
+func (s *FlowService) getWithSource(ctx context.Context, namespace string, flowID string) (*Flow, *Response, error) {
+	apiEndpoint := s.client.apiPath(ctx, "flows/%s/%s?source=true", namespace, flowID)
 	req, err := s.client.NewRequest(ctx, http.MethodGet, apiEndpoint, nil, "")
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
 
 	flow := new(Flow)
 	resp, err := s.client.Do(req, flow)
-	if resp.StatusCode == 404 {
-		return "", resp, nil
+	if errors.Is(err, ErrNotFound) {
+		return nil, resp, nil
+	}
+	if err != nil {
+		return nil, resp, err
 	}
 
-	return flow.Source, resp, nil
+	return flow, resp, nil
 }
 
-func (s *FlowService) Search(ctx context.Context, query string) (*SearchResult, *Response, error) {
-	apiEndpoint := fmt.Sprintf("/api/v1/flows/search?q=%s", query)
+// Search returns a page of the flows matching opts. opts may be nil to list every flow.
+// A 404 response is not an error: a nil result is returned along with the response.
+func (s *FlowService) Search(ctx context.Context, opts *FlowSearchOptions) (*SearchResult, *Response, error) {
+	params := url.Values{}
+	if opts != nil {
+		opts.FlowFilter.addValues(params)
+		opts.ListOptions.addValues(params)
+	}
+
+	apiEndpoint := s.client.apiPath(ctx, "flows/search") + "?" + params.Encode()
 	req, err := s.client.NewRequest(ctx, http.MethodGet, apiEndpoint, nil, "")
 	if err != nil {
 		return nil, nil, err
@@ -98,28 +466,75 @@ func (s *FlowService) Search(ctx context.Context, query string) (*SearchResult,
 
 	searchResult := new(SearchResult)
 	resp, err := s.client.Do(req, searchResult)
-	if resp.StatusCode == 404 {
+	if errors.Is(err, ErrNotFound) {
 		return nil, resp, nil
 	}
+	if err != nil {
+		return nil, resp, err
+	}
 
 	return searchResult, resp, nil
 }
 
+// SearchAll returns an iterator over every flow matching opts, fetching the following
+// pages as needed. opts may be nil.
+func (s *FlowService) SearchAll(ctx context.Context, opts *FlowSearchOptions) iter.Seq2[Flow, error] {
+	search := FlowSearchOptions{}
+	if opts != nil {
+		search = *opts
+	}
+
+	// each page is fetched with a copy, so that search stays unchanged and the iterator can be reused
+	return paginate(&search.ListOptions, func(page *ListOptions) (*SearchResult, *Response, error) {
+		pageSearch := search
+		pageSearch.ListOptions = *page
+		return s.Search(ctx, &pageSearch)
+	})
+}
+
+// Create creates a new flow from its JSON definition.
+// Any non-2xx response, including an invalid or an existing flow (both ErrUnprocessable), is returned as
+// an *APIError.
 func (s *FlowService) Create(ctx context.Context, content string) (*Flow, *Response, error) {
-	apiEndpoint := fmt.Sprintf("/api/v1/flows")
-	req, err := s.client.NewRequest(ctx, http.MethodPost, apiEndpoint, &content, "application/json")
+	return s.create(ctx, content, "application/json")
+}
+
+// CreateFromYAML creates a new flow from its YAML source, which the server stores as is.
+// Errors are returned as for Create.
+func (s *FlowService) CreateFromYAML(ctx context.Context, source string) (*Flow, *Response, error) {
+	return s.create(ctx, source, "application/x-yaml")
+}
+
+// CreateFromFlow creates a new flow from its model, sent as the canonical YAML of MarshalFlowYAML.
+// Errors are returned as for Create.
+func (s *FlowService) CreateFromFlow(ctx context.Context, flow *Flow) (*Flow, *Response, error) {
+	source, err := MarshalFlowYAML(flow)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.create(ctx, source, "application/x-yaml")
+}
+
+func (s *FlowService) create(ctx context.Context, content string, contentType string) (*Flow, *Response, error) {
+	apiEndpoint := s.client.apiPath(ctx, "flows")
+	req, err := s.client.NewRequest(ctx, http.MethodPost, apiEndpoint, &content, contentType)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	flow := new(Flow)
 	resp, err := s.client.Do(req, flow)
+	if err != nil {
+		return nil, resp, err
+	}
 
 	return flow, resp, nil
 }
 
+// Update replaces the flow identified by namespace and ID with the given YAML source.
+// Any non-2xx response, including a missing flow (ErrNotFound), is returned as an *APIError.
 func (s *FlowService) Update(ctx context.Context, namespace string, flowID string, content string) (*Flow, *Response, error) {
-	apiEndpoint := fmt.Sprintf("/api/v1/flows/%s/%s", namespace, flowID)
+	apiEndpoint := s.client.apiPath(ctx, "flows/%s/%s", namespace, flowID)
 	req, err := s.client.NewRequest(ctx, http.MethodPut, apiEndpoint, &content, "application/x-yaml")
 	if err != nil {
 		return nil, nil, err
@@ -127,6 +542,105 @@ func (s *FlowService) Update(ctx context.Context, namespace string, flowID strin
 
 	flow := new(Flow)
 	resp, err := s.client.Do(req, flow)
+	if err != nil {
+		return nil, resp, err
+	}
 
 	return flow, resp, nil
 }
+
+// Delete deletes the flow identified by namespace and ID.
+// Any non-2xx response, including a missing flow (ErrNotFound), is returned as an *APIError.
+func (s *FlowService) Delete(ctx context.Context, namespace string, flowID string) (*Response, error) {
+	apiEndpoint := s.client.apiPath(ctx, "flows/%s/%s", namespace, flowID)
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, apiEndpoint, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(req, nil)
+}
+
+// DeleteByIDs deletes the given flows with a single bulk request.
+// The outcomes are derived from the number of deleted flows reported by the server: FlowDeleted if it
+// deleted every flow, FlowNotFound if it deleted none, and FlowUnknown otherwise, as it does not tell
+// which flows were missing. No request is sent for an empty list.
+func (s *FlowService) DeleteByIDs(ctx context.Context, flows []FlowRef) (*BulkResult, *Response, error) {
+	if err := s.client.requireVersion("bulk flow actions", bulkFlowsVersion); err != nil {
+		return nil, nil, err
+	}
+
+	result := &BulkResult{Outcomes: make([]FlowOutcome, 0, len(flows))}
+	if len(flows) == 0 {
+		return result, nil, nil
+	}
+	for _, ref := range flows {
+		result.Outcomes = append(result.Outcomes, FlowOutcome{FlowRef: ref, Status: FlowDeleted})
+	}
+
+	resp, err := s.bulkByIDs(ctx, http.MethodDelete, "flows/delete/by-ids", flows, result)
+	if err != nil {
+		return nil, resp, err
+	}
+	settleOutcomes(result.Outcomes, result.Count, FlowNotFound)
+
+	return result, resp, nil
+}
+
+// bulkByIDs sends the bulk request of path for the given flows, decoding the count into result.
+func (s *FlowService) bulkByIDs(ctx context.Context, method string, path string, flows []FlowRef, result *BulkResult) (*Response, error) {
+	body, err := json.Marshal(flows)
+	if err != nil {
+		return nil, err
+	}
+	content := string(body)
+
+	apiEndpoint := s.client.apiPath(ctx, path)
+	req, err := s.client.NewRequest(ctx, method, apiEndpoint, &content, "application/json")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(req, result)
+}
+
+// DeleteByQuery deletes every flow matching filter with a single bulk request.
+// The matching flows are listed first to report them in the outcomes, which are settled against the number
+// of deleted flows reported by the server as for DeleteByIDs. No bulk request is sent if no flow matches.
+// As deleting every flow is rarely intended, an empty filter is rejected.
+func (s *FlowService) DeleteByQuery(ctx context.Context, filter *FlowFilter) (*BulkResult, *Response, error) {
+	if err := s.client.requireVersion("bulk flow actions", bulkFlowsVersion); err != nil {
+		return nil, nil, err
+	}
+	if filter.isZero() {
+		return nil, nil, errors.New("kestra: DeleteByQuery requires a non-empty filter")
+	}
+
+	result := &BulkResult{Outcomes: []FlowOutcome{}}
+	for flow, err := range s.SearchAll(ctx, &FlowSearchOptions{FlowFilter: *filter}) {
+		if err != nil {
+			return nil, nil, err
+		}
+		result.Outcomes = append(result.Outcomes, FlowOutcome{FlowRef: FlowRef{Namespace: flow.Namespace, ID: flow.ID}, Status: FlowDeleted})
+	}
+	if len(result.Outcomes) == 0 {
+		return result, nil, nil
+	}
+
+	params := url.Values{}
+	filter.addValues(params)
+
+	apiEndpoint := s.client.apiPath(ctx, "flows/delete/by-query") + "?" + params.Encode()
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, apiEndpoint, nil, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, err
+	}
+	settleOutcomes(result.Outcomes, result.Count, FlowNotFound)
+
+	return result, resp, nil
+}
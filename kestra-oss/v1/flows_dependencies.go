@@ -0,0 +1,303 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// DependencyRelation is the kind of a dependency between two flows.
+type DependencyRelation string
+
+const (
+	// DependencyFlowTask is the relation of a flow (the source) running another one (the target) as a subflow.
+	DependencyFlowTask DependencyRelation = "FLOW_TASK"
+	// DependencyFlowTrigger is the relation of a flow (the source) whose executions trigger another one (the target)
+	// through a Flow trigger.
+	DependencyFlowTrigger DependencyRelation = "FLOW_TRIGGER"
+)
+
+// DependencyNode is a flow of a dependency graph.
+type DependencyNode struct {
+	// UID identifies the node in the edges of the graph.
+	UID       string `json:"uid" structs:"uid"`
+	TenantID  string `json:"tenantId,omitempty" structs:"tenantId,omitempty"`
+	Namespace string `json:"namespace" structs:"namespace"`
+	ID        string `json:"id" structs:"id"`
+}
+
+// Ref returns the namespace and ID of the flow of the node.
+func (n DependencyNode) Ref() FlowRef {
+	return FlowRef{Namespace: n.Namespace, ID: n.ID}
+}
+
+// DependencyEdge is a dependency between two flows, from the flow running first (Source) to the flow
+// it runs or triggers (Target).
+type DependencyEdge struct {
+	Source   string             `json:"source" structs:"source"`
+	Target   string             `json:"target" structs:"target"`
+	Relation DependencyRelation `json:"relation" structs:"relation"`
+}
+
+// DependencyGraph is a graph of dependencies between flows.
+type DependencyGraph struct {
+	Nodes []DependencyNode `json:"nodes" structs:"nodes"`
+	Edges []DependencyEdge `json:"edges" structs:"edges"`
+}
+
+// Node returns the node of a flow, or false if the flow is not in the graph.
+func (g *DependencyGraph) Node(flow FlowRef) (DependencyNode, bool) {
+	for _, node := range g.Nodes {
+		if node.Namespace == flow.Namespace && node.ID == flow.ID {
+			return node, true
+		}
+	}
+	return DependencyNode{}, false
+}
+
+// Dependents returns the flows that may break when flow changes, directly or transitively: the flows
+// running it as a subflow, and the flows triggered by its executions. They are sorted by namespace and ID.
+func (g *DependencyGraph) Dependents(flow FlowRef) []FlowRef {
+	start, ok := g.Node(flow)
+	if !ok {
+		return nil
+	}
+
+	// dependents[uid] are the nodes depending directly on uid
+	dependents := make(map[string][]string)
+	for _, edge := range g.Edges {
+		switch edge.Relation {
+		case DependencyFlowTask:
+			dependents[edge.Target] = append(dependents[edge.Target], edge.Source)
+		case DependencyFlowTrigger:
+			dependents[edge.Source] = append(dependents[edge.Source], edge.Target)
+		}
+	}
+
+	seen := map[string]bool{start.UID: true}
+	queue := []string{start.UID}
+	for len(queue) > 0 {
+		uid := queue[0]
+		queue = queue[1:]
+		for _, dependent := range dependents[uid] {
+			if !seen[dependent] {
+				seen[dependent] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	var refs []FlowRef
+	for _, node := range g.Nodes {
+		if seen[node.UID] && node.UID != start.UID {
+			refs = append(refs, node.Ref())
+		}
+	}
+	return refs
+}
+
+// Subgraph returns the part of the graph around flow: the flows it has a dependency with and, if transitive
+// is set, the flows connected to it through any number of dependencies, as Dependencies does on the server.
+func (g *DependencyGraph) Subgraph(flow FlowRef, transitive bool) *DependencyGraph {
+	sub := &DependencyGraph{Nodes: []DependencyNode{}, Edges: []DependencyEdge{}}
+	start, ok := g.Node(flow)
+	if !ok {
+		return sub
+	}
+
+	neighbors := make(map[string][]string)
+	for _, edge := range g.Edges {
+		neighbors[edge.Source] = append(neighbors[edge.Source], edge.Target)
+		neighbors[edge.Target] = append(neighbors[edge.Target], edge.Source)
+	}
+
+	seen := map[string]bool{start.UID: true}
+	queue := []string{start.UID}
+	for len(queue) > 0 {
+		uid := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range neighbors[uid] {
+			if !seen[neighbor] {
+				seen[neighbor] = true
+				if transitive {
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+	}
+
+	for _, node := range g.Nodes {
+		if seen[node.UID] {
+			sub.Nodes = append(sub.Nodes, node)
+		}
+	}
+	for _, edge := range g.Edges {
+		if transitive && seen[edge.Source] || edge.Source == start.UID || edge.Target == start.UID {
+			sub.Edges = append(sub.Edges, edge)
+		}
+	}
+	return sub
+}
+
+// Dependencies returns the dependency graph of a flow: the flows it runs as subflows or triggers, and the flows
+// running or triggering it. With transitive, the graph is expanded to every connected flow.
+// A missing flow is not an error: a nil graph is returned along with the 404 response.
+func (s *FlowService) Dependencies(ctx context.Context, namespace string, flowID string, transitive bool) (*DependencyGraph, *Response, error) {
+	if err := s.client.requireVersion("flow dependencies", flowDependenciesVersion); err != nil {
+		return nil, nil, err
+	}
+
+	apiEndpoint := s.client.apiPath(ctx, "flows/%s/%s/dependencies", namespace, flowID) + "?expandAll=" + strconv.FormatBool(transitive)
+	req, err := s.client.NewRequest(ctx, http.MethodGet, apiEndpoint, nil, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	graph := new(DependencyGraph)
+	resp, err := s.client.Do(req, graph)
+	if errors.Is(err, ErrNotFound) {
+		return nil, resp, nil
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return graph, resp, nil
+}
+
+var (
+	// subflowTaskTypes are the types of the tasks running a subflow, given by their namespace and flowId properties.
+	subflowTaskTypes = map[string]bool{
+		"io.kestra.plugin.core.flow.Subflow":     true,
+		"io.kestra.plugin.core.flow.ForEachItem": true,
+		"io.kestra.core.tasks.flows.Subflow":     true,
+		"io.kestra.core.tasks.flows.Flow":        true,
+		"io.kestra.core.tasks.flows.ForEachItem": true,
+	}
+	// flowTriggerTypes are the types of the triggers starting a flow when other flows end.
+	flowTriggerTypes = map[string]bool{
+		"io.kestra.plugin.core.trigger.Flow":        true,
+		"io.kestra.core.models.triggers.types.Flow": true,
+	}
+	// executionFlowConditionTypes are the types of the Flow trigger conditions naming the upstream flow.
+	executionFlowConditionTypes = map[string]bool{
+		"io.kestra.plugin.core.condition.ExecutionFlow":                 true,
+		"io.kestra.plugin.core.condition.ExecutionFlowCondition":        true,
+		"io.kestra.core.models.conditions.types.ExecutionFlowCondition": true,
+	}
+)
+
+// AnalyzeDependencies computes the dependency graph of a set of flows from their definitions, without a server:
+// a FLOW_TASK edge is added for each Subflow (or ForEachItem) task, and a FLOW_TRIGGER edge for each Flow trigger
+// naming an upstream flow in its conditions or preconditions. Flows referenced but not in the set are added as
+// nodes too. Use Subgraph to get the graph of a single flow.
+func AnalyzeDependencies(flows []Flow) *DependencyGraph {
+	g := &dependencyBuilder{nodes: make(map[string]DependencyNode), edges: make(map[DependencyEdge]bool)}
+
+	for _, flow := range flows {
+		ref := FlowRef{Namespace: flow.Namespace, ID: flow.ID}
+		g.node(ref)
+
+		for _, tasks := range [][]FlowTask{flow.Tasks, flow.Errors, flow.Finally} {
+			for _, task := range tasks {
+				g.tasks(ref, task)
+			}
+		}
+
+		for _, trigger := range flow.Triggers {
+			// a trigger that cannot be decoded names no upstream flow
+			execution, err := trigger.FlowExecution()
+			if execution == nil || err != nil {
+				continue
+			}
+			for _, upstream := range execution.UpstreamFlows() {
+				g.edge(upstream, ref, DependencyFlowTrigger)
+			}
+		}
+	}
+
+	return g.graph()
+}
+
+// dependencyBuilder collects the nodes and edges of AnalyzeDependencies.
+type dependencyBuilder struct {
+	nodes map[string]DependencyNode
+	edges map[DependencyEdge]bool
+}
+
+func (b *dependencyBuilder) node(ref FlowRef) string {
+	uid := ref.Namespace + "_" + ref.ID
+	b.nodes[uid] = DependencyNode{UID: uid, Namespace: ref.Namespace, ID: ref.ID}
+	return uid
+}
+
+func (b *dependencyBuilder) edge(source, target FlowRef, relation DependencyRelation) {
+	b.edges[DependencyEdge{Source: b.node(source), Target: b.node(target), Relation: relation}] = true
+}
+
+// tasks adds the edges of task and of its subtasks, typed or nested in its properties (e.g. then, else or cases).
+func (b *dependencyBuilder) tasks(flow FlowRef, task FlowTask) {
+	if subflowTaskTypes[task.Type] {
+		namespace, _ := task.Properties["namespace"].(string)
+		id, _ := task.Properties["flowId"].(string)
+		if namespace != "" && id != "" {
+			b.edge(flow, FlowRef{Namespace: namespace, ID: id}, DependencyFlowTask)
+		}
+	}
+	for _, subtasks := range [][]FlowTask{task.Tasks, task.Errors, task.Finally} {
+		for _, subtask := range subtasks {
+			b.tasks(flow, subtask)
+		}
+	}
+	b.properties(flow, task.Properties)
+}
+
+// properties looks for the subflow tasks nested in the untyped properties of a task.
+func (b *dependencyBuilder) properties(flow FlowRef, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if taskType, _ := v["type"].(string); subflowTaskTypes[taskType] {
+			namespace, _ := v["namespace"].(string)
+			id, _ := v["flowId"].(string)
+			if namespace != "" && id != "" {
+				b.edge(flow, FlowRef{Namespace: namespace, ID: id}, DependencyFlowTask)
+			}
+		}
+		for _, property := range v {
+			b.properties(flow, property)
+		}
+	case []interface{}:
+		for _, item := range v {
+			b.properties(flow, item)
+		}
+	}
+}
+
+func (b *dependencyBuilder) graph() *DependencyGraph {
+	g := &DependencyGraph{Nodes: make([]DependencyNode, 0, len(b.nodes)), Edges: make([]DependencyEdge, 0, len(b.edges))}
+	for _, node := range b.nodes {
+		g.Nodes = append(g.Nodes, node)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		if g.Nodes[i].Namespace != g.Nodes[j].Namespace {
+			return g.Nodes[i].Namespace < g.Nodes[j].Namespace
+		}
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+	for edge := range b.edges {
+		g.Edges = append(g.Edges, edge)
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return a.Relation < b.Relation
+	})
+	return g
+}
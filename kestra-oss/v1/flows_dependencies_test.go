@@ -0,0 +1,205 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestFlowService_Dependencies(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/flows/company/shared/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testRequestParams(t, r, map[string]string{"expandAll": "true"})
+		fmt.Fprint(w, `{"nodes":[{"uid":"company_parent","namespace":"company","id":"parent"},{"uid":"company_shared","namespace":"company","id":"shared"}],"edges":[{"source":"company_parent","target":"company_shared","relation":"FLOW_TASK"}]}`)
+	})
+
+	tests := []struct {
+		name   string
+		flowID string
+		want   *DependencyGraph
+		code   int
+	}{
+		{"should get dependencies", "shared", &DependencyGraph{
+			Nodes: []DependencyNode{
+				{UID: "company_parent", Namespace: "company", ID: "parent"},
+				{UID: "company_shared", Namespace: "company", ID: "shared"},
+			},
+			Edges: []DependencyEdge{{Source: "company_parent", Target: "company_shared", Relation: DependencyFlowTask}},
+		}, 200},
+		{"should not find anything", "missing", nil, 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, resp, err := testClient.Flow.Dependencies(context.Background(), "company", tt.flowID, true)
+			if err != nil {
+				t.Fatalf("Dependencies() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Dependencies() got = %v, want %v", got, tt.want)
+			}
+			if resp.StatusCode != tt.code {
+				t.Errorf("StatusCode got = %v, want %v", resp.StatusCode, tt.code)
+			}
+		})
+	}
+}
+
+// dependencySources are a shared flow run as a subflow by parent (nested in an If task), and triggered
+// flows downstream of parent.
+var dependencySources = []string{
+	`id: shared
+namespace: company
+tasks:
+  - id: log
+    type: io.kestra.plugin.core.log.Log
+    message: shared
+`,
+	`id: parent
+namespace: company
+tasks:
+  - id: check
+    type: io.kestra.plugin.core.flow.If
+    condition: "{{ true }}"
+    then:
+      - id: run
+        type: io.kestra.plugin.core.flow.Subflow
+        namespace: company
+        flowId: shared
+`,
+	`id: on_parent
+namespace: company.reports
+tasks:
+  - id: log
+    type: io.kestra.plugin.core.log.Log
+    message: done
+triggers:
+  - id: parent_done
+    type: io.kestra.plugin.core.trigger.Flow
+    conditions:
+      - type: io.kestra.plugin.core.condition.ExecutionFlow
+        namespace: company
+        flowId: parent
+`,
+	`id: on_report
+namespace: company.reports
+tasks:
+  - id: log
+    type: io.kestra.plugin.core.log.Log
+    message: done
+triggers:
+  - id: report_done
+    type: io.kestra.plugin.core.trigger.Flow
+    preconditions:
+      id: report
+      flows:
+        - namespace: company.reports
+          flowId: on_parent
+`,
+	`id: unrelated
+namespace: company
+tasks:
+  - id: run
+    type: io.kestra.plugin.core.flow.Subflow
+    namespace: other
+    flowId: external
+`,
+}
+
+func dependencyFlows(t *testing.T) []Flow {
+	t.Helper()
+	flows := make([]Flow, len(dependencySources))
+	for i, source := range dependencySources {
+		flow, err := ParseFlowYAML(source)
+		if err != nil {
+			t.Fatalf("ParseFlowYAML() error = %v", err)
+		}
+		flows[i] = *flow
+	}
+	return flows
+}
+
+func TestAnalyzeDependencies(t *testing.T) {
+	got := AnalyzeDependencies(dependencyFlows(t))
+	want := &DependencyGraph{
+		Nodes: []DependencyNode{
+			{UID: "company_parent", Namespace: "company", ID: "parent"},
+			{UID: "company_shared", Namespace: "company", ID: "shared"},
+			{UID: "company_unrelated", Namespace: "company", ID: "unrelated"},
+			{UID: "company.reports_on_parent", Namespace: "company.reports", ID: "on_parent"},
+			{UID: "company.reports_on_report", Namespace: "company.reports", ID: "on_report"},
+			{UID: "other_external", Namespace: "other", ID: "external"},
+		},
+		Edges: []DependencyEdge{
+			{Source: "company.reports_on_parent", Target: "company.reports_on_report", Relation: DependencyFlowTrigger},
+			{Source: "company_parent", Target: "company.reports_on_parent", Relation: DependencyFlowTrigger},
+			{Source: "company_parent", Target: "company_shared", Relation: DependencyFlowTask},
+			{Source: "company_unrelated", Target: "other_external", Relation: DependencyFlowTask},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AnalyzeDependencies() got = %v, want %v", got, want)
+	}
+}
+
+func TestDependencyGraph_Dependents(t *testing.T) {
+	graph := AnalyzeDependencies(dependencyFlows(t))
+
+	tests := []struct {
+		name string
+		flow FlowRef
+		want []FlowRef
+	}{
+		{"should follow subflows and triggers", FlowRef{Namespace: "company", ID: "shared"}, []FlowRef{
+			{Namespace: "company", ID: "parent"},
+			{Namespace: "company.reports", ID: "on_parent"},
+			{Namespace: "company.reports", ID: "on_report"},
+		}},
+		{"should not include the subflows", FlowRef{Namespace: "company.reports", ID: "on_parent"}, []FlowRef{
+			{Namespace: "company.reports", ID: "on_report"},
+		}},
+		{"should find no dependent", FlowRef{Namespace: "company", ID: "unrelated"}, nil},
+		{"should ignore a missing flow", FlowRef{Namespace: "company", ID: "missing"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := graph.Dependents(tt.flow); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Dependents() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDependencyGraph_Subgraph(t *testing.T) {
+	graph := AnalyzeDependencies(dependencyFlows(t))
+	parent := FlowRef{Namespace: "company", ID: "parent"}
+
+	tests := []struct {
+		name       string
+		transitive bool
+		nodes      []string
+		edges      int
+	}{
+		{"should keep direct dependencies", false, []string{"company_parent", "company_shared", "company.reports_on_parent"}, 2},
+		{"should expand to connected flows", true, []string{"company_parent", "company_shared", "company.reports_on_parent", "company.reports_on_report"}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := graph.Subgraph(parent, tt.transitive)
+			var nodes []string
+			for _, node := range sub.Nodes {
+				nodes = append(nodes, node.UID)
+			}
+			if !reflect.DeepEqual(nodes, tt.nodes) {
+				t.Errorf("Subgraph() nodes got = %v, want %v", nodes, tt.nodes)
+			}
+			if len(sub.Edges) != tt.edges {
+				t.Errorf("Subgraph() edges got = %v, want %d", sub.Edges, tt.edges)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// Enable enables a flow, so that its triggers start creating executions again.
+// The outcome reports whether the flow changed state; a missing flow is reported with the
+// FlowNotFound status.
+func (s *FlowService) Enable(ctx context.Context, namespace string, flowID string) (*FlowOutcome, *Response, error) {
+	return s.setDisabled(ctx, FlowRef{Namespace: namespace, ID: flowID}, false)
+}
+
+// Disable disables a flow, so that its triggers stop creating executions.
+// The outcome reports whether the flow changed state; a missing flow is reported with the
+// FlowNotFound status.
+func (s *FlowService) Disable(ctx context.Context, namespace string, flowID string) (*FlowOutcome, *Response, error) {
+	return s.setDisabled(ctx, FlowRef{Namespace: namespace, ID: flowID}, true)
+}
+
+// setDisabled looks the flow up first, so that the bulk request is only sent if it changes state.
+func (s *FlowService) setDisabled(ctx context.Context, ref FlowRef, disabled bool) (*FlowOutcome, *Response, error) {
+	if err := s.client.requireVersion("bulk flow actions", bulkFlowsVersion); err != nil {
+		return nil, nil, err
+	}
+
+	flow, resp, err := s.Get(ctx, ref.Namespace, ref.ID)
+	if err != nil {
+		return nil, resp, err
+	}
+	outcome := &FlowOutcome{FlowRef: ref, Status: disabledStatus(flow, disabled)}
+	if !outcome.applied() {
+		return outcome, resp, nil
+	}
+
+	result := &BulkResult{Outcomes: []FlowOutcome{*outcome}}
+	resp, err = s.bulkByIDs(ctx, http.MethodPost, disabledPath(disabled)+"/by-ids", []FlowRef{ref}, result)
+	if err != nil {
+		return nil, resp, err
+	}
+	settleOutcomes(result.Outcomes, result.Count, FlowUnchanged)
+
+	return &result.Outcomes[0], resp, nil
+}
+
+// SetDisabledByIDs disables (or enables, if disabled is false) the given flows with a single bulk request.
+// The outcomes are derived from the number of flows the server reports as changed: FlowDisabled (or
+// FlowEnabled) if it changed every flow, FlowUnchanged if it changed none, e.g. as they are missing or
+// already in the requested state, and FlowUnknown otherwise, as it does not tell which flows changed.
+// No request is sent for an empty list.
+func (s *FlowService) SetDisabledByIDs(ctx context.Context, flows []FlowRef, disabled bool) (*BulkResult, *Response, error) {
+	if err := s.client.requireVersion("bulk flow actions", bulkFlowsVersion); err != nil {
+		return nil, nil, err
+	}
+
+	result := &BulkResult{Outcomes: make([]FlowOutcome, 0, len(flows))}
+	if len(flows) == 0 {
+		return result, nil, nil
+	}
+	status := FlowEnabled
+	if disabled {
+		status = FlowDisabled
+	}
+	for _, ref := range flows {
+		result.Outcomes = append(result.Outcomes, FlowOutcome{FlowRef: ref, Status: status})
+	}
+
+	resp, err := s.bulkByIDs(ctx, http.MethodPost, disabledPath(disabled)+"/by-ids", flows, result)
+	if err != nil {
+		return nil, resp, err
+	}
+	settleOutcomes(result.Outcomes, result.Count, FlowUnchanged)
+
+	return result, resp, nil
+}
+
+// SetDisabledByQuery disables (or enables, if disabled is false) every flow matching filter with a single
+// bulk request, e.g. to pause all the flows of a namespace during a maintenance. The matching flows are
+// listed first to report which of them change state, and the outcomes are settled against the number of
+// changed flows reported by the server as for SetDisabledByIDs. No bulk request is sent if no flow would
+// change state. As for DeleteByQuery, an empty filter is rejected.
+func (s *FlowService) SetDisabledByQuery(ctx context.Context, filter *FlowFilter, disabled bool) (*BulkResult, *Response, error) {
+	if err := s.client.requireVersion("bulk flow actions", bulkFlowsVersion); err != nil {
+		return nil, nil, err
+	}
+	if filter.isZero() {
+		return nil, nil, errors.New("kestra: SetDisabledByQuery requires a non-empty filter")
+	}
+
+	result := &BulkResult{Outcomes: []FlowOutcome{}}
+	changing := false
+	for flow, err := range s.SearchAll(ctx, &FlowSearchOptions{FlowFilter: *filter}) {
+		if err != nil {
+			return nil, nil, err
+		}
+		outcome := FlowOutcome{
+			FlowRef: FlowRef{Namespace: flow.Namespace, ID: flow.ID},
+			Status:  disabledStatus(&flow, disabled),
+		}
+		changing = changing || outcome.applied()
+		result.Outcomes = append(result.Outcomes, outcome)
+	}
+	if !changing {
+		return result, nil, nil
+	}
+
+	params := url.Values{}
+	filter.addValues(params)
+
+	apiEndpoint := s.client.apiPath(ctx, disabledPath(disabled)+"/by-query") + "?" + params.Encode()
+	req, err := s.client.NewRequest(ctx, http.MethodPost, apiEndpoint, nil, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, err
+	}
+	settleOutcomes(result.Outcomes, result.Count, FlowUnchanged)
+
+	return result, resp, nil
+}
+
+// disabledPath returns the path of the bulk endpoints setting the disabled state of flows.
+func disabledPath(disabled bool) string {
+	if disabled {
+		return "flows/disable"
+	}
+	return "flows/enable"
+}
+
+// disabledStatus returns the outcome of setting the disabled state of flow, which may be nil if it does not exist.
+func disabledStatus(flow *Flow, disabled bool) FlowStatus {
+	switch {
+	case flow == nil:
+		return FlowNotFound
+	case flow.Disabled == disabled:
+		return FlowUnchanged
+	case disabled:
+		return FlowDisabled
+	default:
+		return FlowEnabled
+	}
+}
@@ -0,0 +1,177 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestFlowService_SetDisabledByIDs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var count int
+	var bodies = map[string]string{}
+	for _, path := range []string{"/api/v1/flows/disable/by-ids", "/api/v1/flows/enable/by-ids"} {
+		testMux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			body, _ := io.ReadAll(r.Body)
+			bodies[r.URL.Path] = string(body)
+			fmt.Fprintf(w, `{"count":%d}`, count)
+		})
+	}
+	testMux.HandleFunc("/api/v1/flows/tutorial/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("SetDisabledByIDs() requested %s %s", r.Method, r.URL.Path)
+	})
+
+	refs := []FlowRef{{"tutorial", "a"}, {"tutorial", "b"}}
+	body := `[{"namespace":"tutorial","id":"a"},{"namespace":"tutorial","id":"b"}]`
+	tests := []struct {
+		name     string
+		disabled bool
+		count    int
+		path     string
+		want     FlowStatus
+	}{
+		{"should disable every flow", true, 2, "/api/v1/flows/disable/by-ids", FlowDisabled},
+		{"should enable every flow", false, 2, "/api/v1/flows/enable/by-ids", FlowEnabled},
+		{"should report unchanged flows", true, 0, "/api/v1/flows/disable/by-ids", FlowUnchanged},
+		{"should report unknown outcomes", false, 1, "/api/v1/flows/enable/by-ids", FlowUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count = tt.count
+			got, _, err := testClient.Flow.SetDisabledByIDs(context.Background(), refs, tt.disabled)
+			if err != nil {
+				t.Fatalf("SetDisabledByIDs() error = %v", err)
+			}
+			want := &BulkResult{Count: tt.count, Outcomes: []FlowOutcome{{refs[0], tt.want}, {refs[1], tt.want}}}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("SetDisabledByIDs() got = %v, want %v", got, want)
+			}
+			if bodies[tt.path] != body {
+				t.Errorf("Request body got = %v, want %v", bodies[tt.path], body)
+			}
+		})
+	}
+
+	bodies = map[string]string{}
+	got, resp, err := testClient.Flow.SetDisabledByIDs(context.Background(), nil, true)
+	if err != nil || resp != nil || len(got.Outcomes) != 0 || len(bodies) != 0 {
+		t.Errorf("SetDisabledByIDs() with no flow got = %v, %v, error = %v, requests = %v", got, resp, err, bodies)
+	}
+}
+
+func TestFlowService_Disable(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/flows/tutorial/enabled", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"enabled","namespace":"tutorial","disabled":false}`)
+	})
+	testMux.HandleFunc("/api/v1/flows/tutorial/disabled", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"disabled","namespace":"tutorial","disabled":true}`)
+	})
+	testMux.HandleFunc("/api/v1/flows/tutorial/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	var bodies []string
+	for _, path := range []string{"/api/v1/flows/disable/by-ids", "/api/v1/flows/enable/by-ids"} {
+		testMux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, r.URL.Path+" "+string(body))
+			fmt.Fprint(w, `{"count":1}`)
+		})
+	}
+
+	tests := []struct {
+		name     string
+		call     func(ctx context.Context, namespace string, flowID string) (*FlowOutcome, *Response, error)
+		flowID   string
+		want     FlowStatus
+		wantBody []string
+	}{
+		{"should disable an enabled flow", testClient.Flow.Disable, "enabled", FlowDisabled,
+			[]string{`/api/v1/flows/disable/by-ids [{"namespace":"tutorial","id":"enabled"}]`}},
+		{"should enable a disabled flow", testClient.Flow.Enable, "disabled", FlowEnabled,
+			[]string{`/api/v1/flows/enable/by-ids [{"namespace":"tutorial","id":"disabled"}]`}},
+		{"should leave a disabled flow", testClient.Flow.Disable, "disabled", FlowUnchanged, nil},
+		{"should report a missing flow", testClient.Flow.Enable, "missing", FlowNotFound, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bodies = nil
+			got, _, err := tt.call(context.Background(), "tutorial", tt.flowID)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if want := (FlowOutcome{FlowRef{"tutorial", tt.flowID}, tt.want}); *got != want {
+				t.Errorf("got = %v, want %v", got, want)
+			}
+			if !reflect.DeepEqual(bodies, tt.wantBody) {
+				t.Errorf("requests = %q, want %q", bodies, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestFlowService_SetDisabledByQuery(t *testing.T) {
+	setup()
+	defer teardown()
+
+	results := `[{"id":"a","namespace":"tutorial"},{"id":"b","namespace":"tutorial","disabled":true},{"id":"c","namespace":"tutorial"}]`
+	count, requests := 2, 0
+	testMux.HandleFunc("/api/v1/flows/search", func(w http.ResponseWriter, r *http.Request) {
+		testRequestParams(t, r, map[string]string{"namespace": "tutorial", "page": "1", "size": "50"})
+		fmt.Fprintf(w, `{"results":%s,"total":3}`, results)
+	})
+	testMux.HandleFunc("/api/v1/flows/disable/by-query", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		testRequestParams(t, r, map[string]string{"namespace": "tutorial"})
+		requests++
+		fmt.Fprintf(w, `{"count":%d}`, count)
+	})
+
+	tests := []struct {
+		name         string
+		results      string
+		count        int
+		want         *BulkResult
+		wantRequests int
+	}{
+		{"should disable the matching flows", results, 2, &BulkResult{Count: 2, Outcomes: []FlowOutcome{
+			{FlowRef{"tutorial", "a"}, FlowDisabled},
+			{FlowRef{"tutorial", "b"}, FlowUnchanged},
+			{FlowRef{"tutorial", "c"}, FlowDisabled},
+		}}, 1},
+		{"should report unknown outcomes", results, 1, &BulkResult{Count: 1, Outcomes: []FlowOutcome{
+			{FlowRef{"tutorial", "a"}, FlowUnknown},
+			{FlowRef{"tutorial", "b"}, FlowUnchanged},
+			{FlowRef{"tutorial", "c"}, FlowUnknown},
+		}}, 1},
+		{"should skip the request when nothing changes", `[{"id":"b","namespace":"tutorial","disabled":true}]`, 0,
+			&BulkResult{Outcomes: []FlowOutcome{{FlowRef{"tutorial", "b"}, FlowUnchanged}}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, count, requests = tt.results, tt.count, 0
+			got, _, err := testClient.Flow.SetDisabledByQuery(context.Background(), &FlowFilter{Namespace: "tutorial"}, true)
+			if err != nil {
+				t.Fatalf("SetDisabledByQuery() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SetDisabledByQuery() got = %v, want %v", got, tt.want)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("SetDisabledByQuery() sent %d bulk requests, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+
+	if _, _, err := testClient.Flow.SetDisabledByQuery(context.Background(), nil, true); err == nil {
+		t.Errorf("SetDisabledByQuery() with a nil filter error = nil, want error")
+	}
+}
@@ -0,0 +1,97 @@
+package v1
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+)
+
+// zipSignature is the signature starting ZIP archives.
+var zipSignature = []byte("PK\x03\x04")
+
+// ExportByQuery writes to w the ZIP archive of the YAML sources of every flow matching filter.
+// A nil filter exports every flow. The archive is streamed to w as it is received.
+func (s *FlowService) ExportByQuery(ctx context.Context, filter *FlowFilter, w io.Writer) (*Response, error) {
+	if err := s.client.requireVersion("bulk flow actions", bulkFlowsVersion); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	filter.addValues(params)
+
+	apiEndpoint := s.client.apiPath(ctx, "flows/export/by-query") + "?" + params.Encode()
+	req, err := s.client.NewRequest(ctx, http.MethodGet, apiEndpoint, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(req, w)
+}
+
+// ExportByIDs writes to w the ZIP archive of the YAML sources of the given flows.
+// The archive is streamed to w as it is received.
+func (s *FlowService) ExportByIDs(ctx context.Context, flows []FlowRef, w io.Writer) (*Response, error) {
+	if err := s.client.requireVersion("bulk flow actions", bulkFlowsVersion); err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(flows)
+	if err != nil {
+		return nil, err
+	}
+	content := string(body)
+
+	apiEndpoint := s.client.apiPath(ctx, "flows/export/by-ids")
+	req, err := s.client.NewRequest(ctx, http.MethodPost, apiEndpoint, &content, "application/json")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(req, w)
+}
+
+// Import uploads flows read from r, either a ZIP archive as written by the export methods or a single
+// YAML source. Imported flows that already exist get a new revision. r is streamed to the server as it is
+// read, so that large archives are not held in memory.
+// Any non-2xx response, including invalid flows (ErrUnprocessable), is returned as an *APIError.
+func (s *FlowService) Import(ctx context.Context, r io.Reader) (*Response, error) {
+	if err := s.client.requireVersion("bulk flow actions", bulkFlowsVersion); err != nil {
+		return nil, err
+	}
+
+	// Kestra tells archives from YAML sources by the name of the uploaded file
+	data := bufio.NewReader(r)
+	filename := "flow.yaml"
+	if signature, _ := data.Peek(len(zipSignature)); bytes.Equal(signature, zipSignature) {
+		filename = "flows.zip"
+	}
+
+	body, pw := io.Pipe()
+	defer body.Close()
+	form := multipart.NewWriter(pw)
+
+	apiEndpoint := s.client.apiPath(ctx, "flows/import")
+	req, err := s.client.NewRequest(ctx, http.MethodPost, apiEndpoint, nil, form.FormDataContentType())
+	if err != nil {
+		return nil, err
+	}
+	req.Body = body
+
+	go func() {
+		part, err := form.CreateFormFile("fileUpload", filename)
+		if err == nil {
+			_, err = io.Copy(part, data)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return s.client.Do(req, nil)
+}
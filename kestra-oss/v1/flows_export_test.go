@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestFlowService_Export(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/flows/export/by-query", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testRequestParams(t, r, map[string]string{"namespace": "tutorial"})
+		fmt.Fprint(w, "PK\x03\x04by-query")
+	})
+	testMux.HandleFunc("/api/v1/flows/export/by-ids", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		body, _ := io.ReadAll(r.Body)
+		if got, want := string(body), `[{"namespace":"tutorial","id":"hello_world"}]`; got != want {
+			t.Errorf("Request body got = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, "PK\x03\x04by-ids")
+	})
+
+	tests := []struct {
+		name   string
+		export func(w io.Writer) (*Response, error)
+		want   string
+	}{
+		{"should export by query", func(w io.Writer) (*Response, error) {
+			return testClient.Flow.ExportByQuery(context.Background(), &FlowFilter{Namespace: "tutorial"}, w)
+		}, "PK\x03\x04by-query"},
+		{"should export by IDs", func(w io.Writer) (*Response, error) {
+			return testClient.Flow.ExportByIDs(context.Background(), []FlowRef{{"tutorial", "hello_world"}}, w)
+		}, "PK\x03\x04by-ids"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if _, err := tt.export(&buf); err != nil {
+				t.Fatalf("export error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("export got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlowService_Import(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/flows/import", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		if r.ContentLength != -1 {
+			t.Errorf("ContentLength got = %v, want -1 for a streamed body", r.ContentLength)
+		}
+		file, header, err := r.FormFile("fileUpload")
+		if err != nil {
+			// e.g. the upload of a failing reader, aborted by the client
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		if bytes.Contains(data, []byte("invalid")) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"message":"Invalid entity: flow.tasks: must not be empty"}`)
+			return
+		}
+		w.Header().Set("X-Filename", header.Filename)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name         string
+		data         string
+		wantFilename string
+		wantErr      error
+	}{
+		{"should upload a YAML source", "id: hello_world\nnamespace: tutorial", "flow.yaml", nil},
+		{"should upload an archive", "PK\x03\x04archive", "flows.zip", nil},
+		{"should return invalid flows", "id: invalid", "", ErrUnprocessable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := testClient.Flow.Import(context.Background(), strings.NewReader(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Import() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := resp.Header.Get("X-Filename"); got != tt.wantFilename {
+				t.Errorf("uploaded filename = %v, want %v", got, tt.wantFilename)
+			}
+		})
+	}
+
+	readErr := errors.New("read failed")
+	if _, err := testClient.Flow.Import(context.Background(), iotest.ErrReader(readErr)); !errors.Is(err, readErr) {
+		t.Errorf("Import() with a failing reader error = %v, want %v", err, readErr)
+	}
+}
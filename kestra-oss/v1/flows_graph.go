@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// GraphRelationType is the kind of an edge of a flow graph.
+type GraphRelationType string
+
+const (
+	// GraphSequential links a task to the task run after it.
+	GraphSequential GraphRelationType = "SEQUENTIAL"
+	// GraphParallel links a task to the tasks run in parallel by a Parallel task.
+	GraphParallel GraphRelationType = "PARALLEL"
+	// GraphChoice links a task to the branches of an If or Switch task.
+	GraphChoice GraphRelationType = "CHOICE"
+	// GraphDynamic links a task to the tasks it runs for each value, e.g. of a ForEach task.
+	GraphDynamic GraphRelationType = "DYNAMIC"
+	// GraphError links a task to the tasks run when it fails.
+	GraphError GraphRelationType = "ERROR"
+	// GraphFinally links a task to the tasks run once it ends.
+	GraphFinally GraphRelationType = "FINALLY"
+	// GraphAfterExecution links a flow to the tasks run after its execution ends.
+	GraphAfterExecution GraphRelationType = "AFTER_EXECUTION"
+)
+
+// GraphNode is a node of a flow graph: a task, a trigger, or the start or end of a cluster.
+type GraphNode struct {
+	UID string `json:"uid" structs:"uid"`
+	// Type is the Kestra class of the node, e.g. io.kestra.core.models.hierarchies.GraphTask.
+	Type string `json:"type" structs:"type"`
+	// Task is the task of a task node.
+	Task *FlowTask `json:"task,omitempty" structs:"task,omitempty"`
+	// Trigger is the trigger of a trigger node.
+	Trigger      *FlowTrigger      `json:"trigger,omitempty" structs:"trigger,omitempty"`
+	RelationType GraphRelationType `json:"relationType,omitempty" structs:"relationType,omitempty"`
+	BranchType   string            `json:"branchType,omitempty" structs:"branchType,omitempty"`
+
+	// Extra holds the properties of the node not mapped above.
+	Extra map[string]interface{} `json:"-" structs:"-"`
+}
+
+type graphNode GraphNode
+
+// UnmarshalJSON decodes a node, keeping its unknown properties in Extra.
+func (n *GraphNode) UnmarshalJSON(data []byte) error {
+	*n = GraphNode{}
+	extra, err := unmarshalWithExtra(data, (*graphNode)(n))
+	if err != nil {
+		return err
+	}
+	n.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes a node along with its Extra properties.
+func (n GraphNode) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(graphNode(n), n.Extra)
+}
+
+// Label returns the ID of the task or trigger of the node, or the last part of its UID.
+func (n GraphNode) Label() string {
+	switch {
+	case n.Task != nil && n.Task.ID != "":
+		return n.Task.ID
+	case n.Trigger != nil && n.Trigger.ID != "":
+		return n.Trigger.ID
+	}
+	return n.UID[strings.LastIndex(n.UID, ".")+1:]
+}
+
+// IsTrigger reports whether the node is a trigger.
+func (n GraphNode) IsTrigger() bool {
+	return n.Trigger != nil || strings.HasSuffix(n.Type, ".GraphTrigger")
+}
+
+// IsBoundary reports whether the node is the start or the end of the flow or of a cluster,
+// rather than a task or a trigger.
+func (n GraphNode) IsBoundary() bool {
+	return strings.HasSuffix(n.Type, ".GraphClusterRoot") || strings.HasSuffix(n.Type, ".GraphClusterEnd")
+}
+
+// GraphRelation describes an edge of a flow graph.
+type GraphRelation struct {
+	RelationType GraphRelationType `json:"relationType,omitempty" structs:"relationType,omitempty"`
+	// Value is the branch of the edge, e.g. the case of a Switch task.
+	Value string `json:"value,omitempty" structs:"value,omitempty"`
+}
+
+// GraphEdge is an edge of a flow graph, between the UIDs of two nodes.
+type GraphEdge struct {
+	Source   string        `json:"source" structs:"source"`
+	Target   string        `json:"target" structs:"target"`
+	Relation GraphRelation `json:"relation" structs:"relation"`
+}
+
+// GraphCluster groups the nodes of a flowable task (e.g. Parallel, If or ForEach) or of the triggers.
+type GraphCluster struct {
+	// Cluster is the node of the cluster itself.
+	Cluster GraphNode `json:"cluster" structs:"cluster"`
+	// Nodes are the UIDs of the nodes of the cluster.
+	Nodes []string `json:"nodes" structs:"nodes"`
+	// Parents are the UIDs of the clusters containing the cluster.
+	Parents []string `json:"parents" structs:"parents"`
+	Start   string   `json:"start,omitempty" structs:"start,omitempty"`
+	End     string   `json:"end,omitempty" structs:"end,omitempty"`
+}
+
+// FlowGraph is the topology of a flow, as drawn by the Kestra UI. It is rendered with DOT and Mermaid.
+type FlowGraph struct {
+	Nodes    []GraphNode    `json:"nodes" structs:"nodes"`
+	Edges    []GraphEdge    `json:"edges" structs:"edges"`
+	Clusters []GraphCluster `json:"clusters" structs:"clusters"`
+}
+
+// Graph returns the topology graph of the latest revision of a flow.
+// A missing flow is not an error: a nil graph is returned along with the 404 response.
+func (s *FlowService) Graph(ctx context.Context, namespace string, flowID string) (*FlowGraph, *Response, error) {
+	if err := s.client.requireVersion("flow graph", flowGraphVersion); err != nil {
+		return nil, nil, err
+	}
+
+	apiEndpoint := s.client.apiPath(ctx, "flows/%s/%s/graph", namespace, flowID)
+	req, err := s.client.NewRequest(ctx, http.MethodGet, apiEndpoint, nil, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	graph := new(FlowGraph)
+	resp, err := s.client.Do(req, graph)
+	if errors.Is(err, ErrNotFound) {
+		return nil, resp, nil
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return graph, resp, nil
+}
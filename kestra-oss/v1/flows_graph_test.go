@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestFlowService_Graph(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/flows/tutorial/hello_world/graph", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"nodes":[{"uid":"root.root","type":"io.kestra.core.models.hierarchies.GraphClusterRoot"},{"uid":"root.log","type":"io.kestra.core.models.hierarchies.GraphTask","task":{"id":"log","type":"io.kestra.plugin.core.log.Log","message":"Hello World"},"relationType":"SEQUENTIAL","executionId":null}],"edges":[{"source":"root.root","target":"root.log","relation":{"relationType":"SEQUENTIAL"}}],"clusters":[]}`)
+	})
+
+	tests := []struct {
+		name   string
+		flowID string
+		want   *FlowGraph
+		code   int
+	}{
+		{"should get the graph", "hello_world", &FlowGraph{
+			Nodes: []GraphNode{
+				{UID: "root.root", Type: "io.kestra.core.models.hierarchies.GraphClusterRoot"},
+				{
+					UID:          "root.log",
+					Type:         "io.kestra.core.models.hierarchies.GraphTask",
+					Task:         &helloWorldTask,
+					RelationType: GraphSequential,
+					Extra:        map[string]interface{}{"executionId": nil},
+				},
+			},
+			Edges:    []GraphEdge{{Source: "root.root", Target: "root.log", Relation: GraphRelation{RelationType: GraphSequential}}},
+			Clusters: []GraphCluster{},
+		}, 200},
+		{"should not find anything", "missing", nil, 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, resp, err := testClient.Flow.Graph(context.Background(), "tutorial", tt.flowID)
+			if err != nil {
+				t.Fatalf("Graph() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Graph() got = %+v, want %+v", got, tt.want)
+			}
+			if resp.StatusCode != tt.code {
+				t.Errorf("StatusCode got = %v, want %v", resp.StatusCode, tt.code)
+			}
+		})
+	}
+}
+
+func TestGraphNode_Label(t *testing.T) {
+	tests := []struct {
+		name string
+		node GraphNode
+		want string
+	}{
+		{"should use the task id", GraphNode{UID: "root.parallel.log", Task: &FlowTask{ID: "log"}}, "log"},
+		{"should use the trigger id", GraphNode{UID: "Triggers.daily", Trigger: &FlowTrigger{ID: "daily"}}, "daily"},
+		{"should fall back to the uid", GraphNode{UID: "root.end"}, "end"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.node.Label(); got != tt.want {
+				t.Errorf("Label() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
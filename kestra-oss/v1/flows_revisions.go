@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// Revisions returns every revision of a flow, oldest first, with their sources.
+// A missing flow is not an error: a nil slice is returned along with the 404 response.
+func (s *FlowService) Revisions(ctx context.Context, namespace string, flowID string) ([]Flow, *Response, error) {
+	apiEndpoint := s.client.apiPath(ctx, "flows/%s/%s/revisions", namespace, flowID)
+	req, err := s.client.NewRequest(ctx, http.MethodGet, apiEndpoint, nil, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var revisions []Flow
+	resp, err := s.client.Do(req, &revisions)
+	if errors.Is(err, ErrNotFound) {
+		return nil, resp, nil
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return revisions, resp, nil
+}
+
+// GetRevision returns a revision of a flow, with its source.
+// A missing flow or revision is not an error: a nil flow is returned along with the 404 response.
+func (s *FlowService) GetRevision(ctx context.Context, namespace string, flowID string, revision int) (*Flow, *Response, error) {
+	apiEndpoint := s.client.apiPath(ctx, "flows/%s/%s", namespace, flowID) + "?source=true&revision=" + strconv.Itoa(revision)
+	req, err := s.client.NewRequest(ctx, http.MethodGet, apiEndpoint, nil, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	flow := new(Flow)
+	resp, err := s.client.Do(req, flow)
+	if errors.Is(err, ErrNotFound) {
+		return nil, resp, nil
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return flow, resp, nil
+}
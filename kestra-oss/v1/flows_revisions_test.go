@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestFlowService_Revisions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/flows/tutorial/hello_world/revisions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `[{"id":"hello_world","namespace":"tutorial","revision":1,"source":"id: hello_world\n"},{"id":"hello_world","namespace":"tutorial","revision":2,"source":"id: hello_world\ndescription: Hello\n"}]`)
+	})
+
+	tests := []struct {
+		name   string
+		flowID string
+		want   []Flow
+		code   int
+	}{
+		{"should list revisions", "hello_world", []Flow{
+			{ID: "hello_world", Namespace: "tutorial", Revision: "1", Source: "id: hello_world\n"},
+			{ID: "hello_world", Namespace: "tutorial", Revision: "2", Source: "id: hello_world\ndescription: Hello\n"},
+		}, 200},
+		{"should not find anything", "missing", nil, 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, resp, err := testClient.Flow.Revisions(context.Background(), "tutorial", tt.flowID)
+			if err != nil {
+				t.Fatalf("Revisions() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Revisions() got = %v, want %v", got, tt.want)
+			}
+			if resp.StatusCode != tt.code {
+				t.Errorf("StatusCode got = %v, want %v", resp.StatusCode, tt.code)
+			}
+		})
+	}
+}
+
+func TestFlowService_GetRevision(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/flows/tutorial/hello_world", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testRequestParams(t, r, map[string]string{"source": "true", "revision": "1"})
+		fmt.Fprint(w, `{"id":"hello_world","namespace":"tutorial","revision":1,"source":"id: hello_world\n"}`)
+	})
+
+	tests := []struct {
+		name   string
+		flowID string
+		want   *Flow
+		code   int
+	}{
+		{"should get revision 1", "hello_world", &Flow{ID: "hello_world", Namespace: "tutorial", Revision: "1", Source: "id: hello_world\n"}, 200},
+		{"should not find anything", "missing", nil, 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, resp, err := testClient.Flow.GetRevision(context.Background(), "tutorial", tt.flowID, 1)
+			if err != nil {
+				t.Fatalf("GetRevision() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetRevision() got = %v, want %v", got, tt.want)
+			}
+			if resp.StatusCode != tt.code {
+				t.Errorf("StatusCode got = %v, want %v", resp.StatusCode, tt.code)
+			}
+		})
+	}
+}
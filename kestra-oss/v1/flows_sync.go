@@ -0,0 +1,221 @@
+package v1
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SyncAction is the change SyncNamespace makes, or would make, to a flow. Upsert reports its change
+// with the same actions.
+type SyncAction string
+
+const (
+	// SyncCreate creates a flow missing from the namespace.
+	SyncCreate SyncAction = "CREATE"
+	// SyncUpdate creates a new revision of a flow whose source changed.
+	SyncUpdate SyncAction = "UPDATE"
+	// SyncDelete deletes a flow missing from the sources.
+	SyncDelete SyncAction = "DELETE"
+	// SyncUnchanged leaves a flow whose source did not change as is.
+	SyncUnchanged SyncAction = "UNCHANGED"
+)
+
+// SyncOptions specifies the optional behavior of SyncNamespace.
+type SyncOptions struct {
+	// Delete removes the flows of the namespace that are not in the synced sources.
+	Delete bool
+	// DryRun only computes the plan, without changing anything on the server.
+	DryRun bool
+}
+
+// SyncChange is a planned change of SyncNamespace.
+type SyncChange struct {
+	FlowRef
+	Action SyncAction
+}
+
+// SyncResult is the result of SyncNamespace.
+type SyncResult struct {
+	// Changes are the changes of the plan: the synced flows in the order of their sources,
+	// followed by the deleted flows sorted by ID.
+	Changes []SyncChange
+	// Flows are the flows returned by the server once synced. It is nil for a dry run.
+	Flows []Flow
+}
+
+// SyncNamespace makes the flows of namespace match the given YAML sources, with a single bulk request:
+// missing flows are created, and flows whose source differs get a new revision. With opts.Delete, the
+// flows of the namespace that are not in sources are deleted; otherwise they are left untouched.
+//
+// The flows of the namespace are listed first. The changes of a sync are then told from the revisions
+// returned by the bulk request. With opts.DryRun, nothing is changed: the sources of the namespace are
+// exported instead, the plan is computed by comparing them with the given sources, and the returned
+// response is the one of the export. Every source must be a single YAML document declaring namespace.
+// As deleting every flow of the namespace is rarely intended, opts.Delete requires sources. opts may be nil.
+func (s *FlowService) SyncNamespace(ctx context.Context, namespace string, sources []string, opts *SyncOptions) (*SyncResult, *Response, error) {
+	if opts == nil {
+		opts = &SyncOptions{}
+	}
+	if len(sources) == 0 && opts.Delete {
+		return nil, nil, errors.New("kestra: SyncNamespace requires sources to delete the other flows")
+	}
+
+	ids := make([]string, len(sources))
+	seen := make(map[string]bool, len(sources))
+	for i, source := range sources {
+		flow, err := ParseFlowYAML(source)
+		if err != nil {
+			return nil, nil, fmt.Errorf("kestra: source %d: %w", i, err)
+		}
+		if flow.Namespace != namespace {
+			return nil, nil, fmt.Errorf("kestra: source %d: flow %q is in namespace %q, not %q", i, flow.ID, flow.Namespace, namespace)
+		}
+		if seen[flow.ID] {
+			return nil, nil, fmt.Errorf("kestra: source %d: duplicate flow %q", i, flow.ID)
+		}
+		seen[flow.ID] = true
+		ids[i] = flow.ID
+	}
+
+	content, err := joinYAMLDocuments(sources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.DryRun {
+		stored, resp, err := s.namespaceSources(ctx, namespace)
+		if err != nil {
+			return nil, resp, err
+		}
+		result := &SyncResult{Changes: make([]SyncChange, 0, len(sources))}
+		for i, id := range ids {
+			action := SyncCreate
+			if current, ok := stored[id]; ok {
+				action = SyncUpdate
+				if strings.TrimSpace(current) == strings.TrimSpace(sources[i]) {
+					action = SyncUnchanged
+				}
+			}
+			result.Changes = append(result.Changes, SyncChange{FlowRef: FlowRef{Namespace: namespace, ID: id}, Action: action})
+		}
+		result.Changes = appendSyncDeletes(result.Changes, namespace, stored, seen, opts)
+		return result, resp, nil
+	}
+
+	existing, resp, err := s.GetAll(ctx, namespace)
+	if err != nil {
+		return nil, resp, err
+	}
+	stored := make(map[string]json.Number)
+	if existing != nil {
+		for _, flow := range *existing {
+			stored[flow.ID] = flow.Revision
+		}
+	}
+
+	apiEndpoint := s.client.apiPath(ctx, "flows/%s", namespace) + "?delete=" + strconv.FormatBool(opts.Delete)
+	req, err := s.client.NewRequest(ctx, http.MethodPost, apiEndpoint, &content, "application/x-yaml")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &SyncResult{Flows: []Flow{}}
+	resp, err = s.client.Do(req, &result.Flows)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	// Kestra only creates a new revision of the flows whose source changed
+	synced := make(map[string]json.Number, len(result.Flows))
+	for _, flow := range result.Flows {
+		synced[flow.ID] = flow.Revision
+	}
+	result.Changes = make([]SyncChange, 0, len(sources))
+	for _, id := range ids {
+		action := SyncCreate
+		if revision, ok := stored[id]; ok {
+			action = SyncUnchanged
+			if synced[id] != revision {
+				action = SyncUpdate
+			}
+		}
+		result.Changes = append(result.Changes, SyncChange{FlowRef: FlowRef{Namespace: namespace, ID: id}, Action: action})
+	}
+	result.Changes = appendSyncDeletes(result.Changes, namespace, stored, seen, opts)
+
+	return result, resp, nil
+}
+
+// appendSyncDeletes appends to changes the deletion of the stored flows missing from seen, sorted by ID,
+// if opts.Delete is set.
+func appendSyncDeletes[V any](changes []SyncChange, namespace string, stored map[string]V, seen map[string]bool, opts *SyncOptions) []SyncChange {
+	if !opts.Delete {
+		return changes
+	}
+	var deleted []string
+	for id := range stored {
+		if !seen[id] {
+			deleted = append(deleted, id)
+		}
+	}
+	sort.Strings(deleted)
+	for _, id := range deleted {
+		changes = append(changes, SyncChange{FlowRef: FlowRef{Namespace: namespace, ID: id}, Action: SyncDelete})
+	}
+	return changes
+}
+
+// namespaceSources returns the sources of the flows of namespace, by ID, exported with a single request.
+// The flows of its sub-namespaces are left out.
+func (s *FlowService) namespaceSources(ctx context.Context, namespace string) (map[string]string, *Response, error) {
+	var buf bytes.Buffer
+	resp, err := s.ExportByQuery(ctx, &FlowFilter{Namespace: namespace}, &buf)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		return nil, resp, fmt.Errorf("kestra: reading the export of namespace %q: %w", namespace, err)
+	}
+	sources := make(map[string]string, len(archive.File))
+	for _, file := range archive.File {
+		source, err := readZipFile(file)
+		if err != nil {
+			return nil, resp, fmt.Errorf("kestra: reading %s: %w", file.Name, err)
+		}
+		var flow struct {
+			ID        string `yaml:"id"`
+			Namespace string `yaml:"namespace"`
+		}
+		if err := yaml.Unmarshal([]byte(source), &flow); err != nil {
+			return nil, resp, fmt.Errorf("kestra: reading %s: %w", file.Name, err)
+		}
+		if flow.Namespace == namespace {
+			sources[flow.ID] = source
+		}
+	}
+	return sources, resp, nil
+}
+
+// readZipFile returns the content of a file of a ZIP archive.
+func readZipFile(file *zip.File) (string, error) {
+	r, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	return string(data), err
+}
@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestFlowService_SyncNamespace(t *testing.T) {
+	const (
+		unchanged = "id: unchanged\nnamespace: tutorial\ntasks:\n  - id: log\n    type: io.kestra.plugin.core.log.Log"
+		updated   = "id: updated\nnamespace: tutorial\ndescription: new\ntasks:\n  - id: log\n    type: io.kestra.plugin.core.log.Log"
+		created   = "id: created\nnamespace: tutorial\ntasks:\n  - id: log\n    type: io.kestra.plugin.core.log.Log"
+	)
+
+	setup()
+	defer teardown()
+
+	var requests, posted []string
+	testMux.HandleFunc("/api/v1/flows/tutorial", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `[{"id":"unchanged","namespace":"tutorial","revision":1},{"id":"updated","namespace":"tutorial","revision":1},{"id":"removed","namespace":"tutorial","revision":3}]`)
+		case http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			posted = append(posted, r.URL.Query().Get("delete")+" "+string(body))
+			fmt.Fprint(w, `[{"id":"unchanged","namespace":"tutorial","revision":1},{"id":"updated","namespace":"tutorial","revision":2},{"id":"created","namespace":"tutorial","revision":1}]`)
+		}
+	})
+	testMux.HandleFunc("/api/v1/flows/export/by-query", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		testRequestParams(t, r, map[string]string{"namespace": "tutorial"})
+		archive := zip.NewWriter(w)
+		for name, source := range map[string]string{
+			"tutorial.unchanged.yml": unchanged + "\n",
+			"tutorial.updated.yml":   "id: updated\nnamespace: tutorial\ndescription: old",
+			"tutorial.removed.yml":   "id: removed\nnamespace: tutorial",
+			"tutorial.sub.other.yml": "id: other\nnamespace: tutorial.sub",
+		} {
+			f, _ := archive.Create(name)
+			io.WriteString(f, source)
+		}
+		archive.Close()
+	})
+	testMux.HandleFunc("/api/v1/flows/tutorial/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("SyncNamespace() requested %s %s", r.Method, r.URL.Path)
+	})
+
+	sources := []string{unchanged, updated + "\n", created}
+	plan := []SyncChange{
+		{FlowRef{"tutorial", "unchanged"}, SyncUnchanged},
+		{FlowRef{"tutorial", "updated"}, SyncUpdate},
+		{FlowRef{"tutorial", "created"}, SyncCreate},
+	}
+	dryRun := []string{"GET /api/v1/flows/export/by-query"}
+	sync := []string{"GET /api/v1/flows/tutorial", "POST /api/v1/flows/tutorial"}
+
+	tests := []struct {
+		name         string
+		opts         *SyncOptions
+		wantPlan     []SyncChange
+		wantRequests []string
+		wantPosted   []string
+		wantFlows    int
+	}{
+		{"should plan without deleting", &SyncOptions{DryRun: true}, plan, dryRun, nil, 0},
+		{"should plan deletions", &SyncOptions{DryRun: true, Delete: true},
+			append(plan, SyncChange{FlowRef{"tutorial", "removed"}, SyncDelete}), dryRun, nil, 0},
+		{"should sync without deleting", nil, plan, sync,
+			[]string{"false " + unchanged + "\n---\n" + updated + "\n---\n" + created + "\n"}, 3},
+		{"should sync and delete", &SyncOptions{Delete: true},
+			append(plan, SyncChange{FlowRef{"tutorial", "removed"}, SyncDelete}), sync,
+			[]string{"true " + unchanged + "\n---\n" + updated + "\n---\n" + created + "\n"}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests, posted = nil, nil
+			got, _, err := testClient.Flow.SyncNamespace(context.Background(), "tutorial", sources, tt.opts)
+			if err != nil {
+				t.Fatalf("SyncNamespace() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Changes, tt.wantPlan) {
+				t.Errorf("SyncNamespace() changes = %v, want %v", got.Changes, tt.wantPlan)
+			}
+			if !reflect.DeepEqual(requests, tt.wantRequests) {
+				t.Errorf("requests = %q, want %q", requests, tt.wantRequests)
+			}
+			if !reflect.DeepEqual(posted, tt.wantPosted) {
+				t.Errorf("posted = %q, want %q", posted, tt.wantPosted)
+			}
+			if len(got.Flows) != tt.wantFlows {
+				t.Errorf("SyncNamespace() flows = %v, want %d flows", got.Flows, tt.wantFlows)
+			}
+		})
+	}
+}
+
+func TestFlowService_SyncNamespaceInvalidSources(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []string
+		opts    *SyncOptions
+	}{
+		{"invalid YAML", []string{"id: [a"}, nil},
+		{"other namespace", []string{"id: a\nnamespace: other"}, nil},
+		{"duplicate flow", []string{"id: a\nnamespace: tutorial", "id: a\nnamespace: tutorial\ndescription: again"}, nil},
+		{"several documents", []string{"id: a\nnamespace: tutorial\n---\nid: b\nnamespace: tutorial"}, nil},
+		{"no source to keep", nil, &SyncOptions{Delete: true}},
+		{"no source to keep in a dry run", []string{}, &SyncOptions{Delete: true, DryRun: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := NewClient("http://localhost:1/", nil)
+			if _, _, err := client.Flow.SyncNamespace(context.Background(), "tutorial", tt.sources, tt.opts); err == nil {
+				t.Errorf("SyncNamespace() error = nil, want error")
+			}
+		})
+	}
+}
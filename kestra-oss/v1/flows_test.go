@@ -2,12 +2,28 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"testing"
 )
 
+// helloWorldTask is the task of the Hello World flow served by the tests.
+var helloWorldTask = FlowTask{
+	ID:         "log",
+	Type:       "io.kestra.plugin.core.log.Log",
+	Properties: map[string]interface{}{"message": "Hello World"},
+	Raw:        json.RawMessage(`{"id":"log","type":"io.kestra.plugin.core.log.Log","message":"Hello World"}`),
+}
+
+// helloWorldFlow is the Hello World flow served by the tests, without inputs.
+var helloWorldFlow = Flow{ID: "hello_world", Namespace: "tutorial", Revision: "21", Description: "Hello World", Tasks: []FlowTask{helloWorldTask}}
+
 func TestFlowService_Get(t *testing.T) {
 	setup()
 	defer teardown()
@@ -26,22 +42,21 @@ func TestFlowService_Get(t *testing.T) {
 	}
 	tests := []struct {
 		name    string
-		s       FlowService
+		s       FlowAPI
 		args    args
 		want    *Flow
 		code    int
 		wantErr bool
 	}{
-		{"should find Hello World", *testClient.Flow,
+		{"should find Hello World", testClient.Flow,
 			args{context.Background(), "hello_world", "tutorial"},
-			&Flow{"hello_world", "tutorial", "21", "Hello World",
-				[]FlowTask{{"log", "io.kestra.plugin.core.log.Log", "", nil}},
-				[]FlowInput{{"service_name", "STRING", "Service Name", "What is the name of the service?", "neato", false}},
-				""},
+			&Flow{ID: "hello_world", Namespace: "tutorial", Revision: "21", Description: "Hello World",
+				Tasks:  []FlowTask{helloWorldTask},
+				Inputs: []FlowInput{{ID: "service_name", Type: "STRING", Name: "Service Name", Description: "What is the name of the service?", Defaults: "neato"}}},
 			200,
 			false,
 		},
-		{"should not find anything", *testClient.Flow,
+		{"should not find anything", testClient.Flow,
 			args{context.Background(), "hello_world", "badnamespace"},
 			nil,
 			404,
@@ -88,28 +103,25 @@ func TestFlowService_Search(t *testing.T) {
 	})
 
 	type args struct {
-		ctx   context.Context
-		query string
+		ctx  context.Context
+		opts *FlowSearchOptions
 	}
 	tests := []struct {
 		name    string
-		s       FlowService
+		s       FlowAPI
 		args    args
 		want    *SearchResult
 		code    int
 		wantErr bool
 	}{
-		{"should find Hello World", *testClient.Flow,
-			args{context.Background(), "hello"},
-			&SearchResult{[]Flow{{"hello_world", "tutorial", "21", "Hello World",
-				[]FlowTask{{"log", "io.kestra.plugin.core.log.Log", "", nil}},
-				[]FlowInput{},
-				""}}, 1},
+		{"should find Hello World", testClient.Flow,
+			args{context.Background(), &FlowSearchOptions{FlowFilter: FlowFilter{Query: "hello"}}},
+			&SearchResult{[]Flow{helloWorldFlow}, 1},
 			200,
 			false,
 		},
-		{"should not find Hello World", *testClient.Flow,
-			args{context.Background(), "another"},
+		{"should not find Hello World", testClient.Flow,
+			args{context.Background(), &FlowSearchOptions{FlowFilter: FlowFilter{Query: "another"}}},
 			nil,
 			404,
 			false,
@@ -117,7 +129,7 @@ func TestFlowService_Search(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, resp, err := tt.s.Search(tt.args.ctx, tt.args.query)
+			got, resp, err := tt.s.Search(tt.args.ctx, tt.args.opts)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Search() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -132,6 +144,31 @@ func TestFlowService_Search(t *testing.T) {
 	}
 }
 
+func TestFlowService_SearchOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/flows/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		if want := "labels=team%3Apayments&namespace=company.data&page=2&q=daily+report&size=10&sort=id%3Adesc"; r.URL.RawQuery != want {
+			t.Errorf("Request query got = %v, want %v", r.URL.RawQuery, want)
+		}
+		fmt.Fprint(w, `{"results":[],"total":11}`)
+	})
+
+	opts := &FlowSearchOptions{
+		FlowFilter:  FlowFilter{Query: "daily report", Namespace: "company.data", Labels: map[string]string{"team": "payments"}},
+		ListOptions: ListOptions{Page: 2, Size: 10, Sort: []string{SortBy("id", SortDesc)}},
+	}
+	result, resp, err := testClient.Flow.Search(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if result.Total != 11 || resp.Total != 11 {
+		t.Errorf("Search() got Total = %d, Response.Total = %d, want 11", result.Total, resp.Total)
+	}
+}
+
 func TestFlowService_Create(t *testing.T) {
 	setup()
 	defer teardown()
@@ -149,15 +186,15 @@ func TestFlowService_Create(t *testing.T) {
 	}
 	tests := []struct {
 		name    string
-		s       FlowService
+		s       FlowAPI
 		args    args
 		want    *Flow
 		code    int
 		wantErr bool
 	}{
-		{"should create Hello World", *testClient.Flow,
+		{"should create Hello World", testClient.Flow,
 			args{context.Background(), "hello_world"},
-			&Flow{"hello_world", "tutorial", "21", "Hello World", []FlowTask{{"log", "io.kestra.plugin.core.log.Log", "", nil}}, []FlowInput{}, ""},
+			&helloWorldFlow,
 			200,
 			false,
 		},
@@ -179,6 +216,34 @@ func TestFlowService_Create(t *testing.T) {
 	}
 }
 
+func TestFlowService_CreateFromYAML(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var got []string
+	testMux.HandleFunc("/api/v1/flows", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		body, _ := io.ReadAll(r.Body)
+		got = append(got, r.Header.Get("Content-Type")+" "+string(body))
+		fmt.Fprint(w, `{"id":"hello_world","namespace":"tutorial","revision":21,"description":"Hello World","tasks":[{"id":"log","type":"io.kestra.plugin.core.log.Log","message":"Hello World"}]}`)
+	})
+
+	source := "id: hello_world\nnamespace: tutorial\ndescription: Hello World\ntasks:\n  - id: log\n    type: io.kestra.plugin.core.log.Log\n    message: Hello World\n"
+	flow, _, err := testClient.Flow.CreateFromYAML(context.Background(), source)
+	if err != nil || !reflect.DeepEqual(flow, &helloWorldFlow) {
+		t.Errorf("CreateFromYAML() got = %v, error = %v", flow, err)
+	}
+
+	flow, _, err = testClient.Flow.CreateFromFlow(context.Background(), &helloWorldFlow)
+	if err != nil || !reflect.DeepEqual(flow, &helloWorldFlow) {
+		t.Errorf("CreateFromFlow() got = %v, error = %v", flow, err)
+	}
+
+	if want := []string{"application/x-yaml " + source, "application/x-yaml " + source}; !reflect.DeepEqual(got, want) {
+		t.Errorf("posted = %q, want %q", got, want)
+	}
+}
+
 func TestFlowService_GetAll(t *testing.T) {
 	setup()
 	defer teardown()
@@ -196,19 +261,19 @@ func TestFlowService_GetAll(t *testing.T) {
 	}
 	tests := []struct {
 		name    string
-		s       FlowService
+		s       FlowAPI
 		args    args
 		want    *[]Flow
 		code    int
 		wantErr bool
 	}{
-		{"should get Hello World", *testClient.Flow,
+		{"should get Hello World", testClient.Flow,
 			args{context.Background(), "tutorial"},
-			&[]Flow{{"hello_world", "tutorial", "21", "Hello World", []FlowTask{{"log", "io.kestra.plugin.core.log.Log", "", nil}}, []FlowInput{}, ""}},
+			&[]Flow{helloWorldFlow},
 			200,
 			false,
 		},
-		{"should not get Hello World", *testClient.Flow,
+		{"should not get Hello World", testClient.Flow,
 			args{context.Background(), "another"},
 			nil,
 			404,
@@ -231,3 +296,321 @@ func TestFlowService_GetAll(t *testing.T) {
 		})
 	}
 }
+
+func TestFlowService_Errors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/flows", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"message":"Invalid entity: flow.id: must not be null"}`)
+	})
+	testMux.HandleFunc("/api/v1/flows/tutorial/broken", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":`)
+	})
+	testMux.HandleFunc("/api/v1/flows/tutorial/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	// a client pointing to a closed server, to produce transport errors
+	closedServer := httptest.NewServer(http.NotFoundHandler())
+	closedServer.Close()
+	closedClient, _ := NewClient(closedServer.URL, nil)
+
+	tests := []struct {
+		name     string
+		call     func() (*Response, error)
+		code     int
+		sentinel error
+	}{
+		{"Create should return API errors", func() (*Response, error) {
+			_, resp, err := testClient.Flow.Create(context.Background(), "{}")
+			return resp, err
+		}, 422, ErrUnprocessable},
+		{"Update should return not found", func() (*Response, error) {
+			_, resp, err := testClient.Flow.Update(context.Background(), "tutorial", "missing", "id: missing")
+			return resp, err
+		}, 404, ErrNotFound},
+		{"Get should return decoding errors", func() (*Response, error) {
+			_, resp, err := testClient.Flow.Get(context.Background(), "tutorial", "broken")
+			return resp, err
+		}, 200, io.ErrUnexpectedEOF},
+		{"Get should return transport errors", func() (*Response, error) {
+			_, resp, err := closedClient.Flow.Get(context.Background(), "tutorial", "hello_world")
+			return resp, err
+		}, 0, nil},
+		{"GetAll should return transport errors", func() (*Response, error) {
+			_, resp, err := closedClient.Flow.GetAll(context.Background(), "tutorial")
+			return resp, err
+		}, 0, nil},
+		{"GetSource should return transport errors", func() (*Response, error) {
+			_, resp, err := closedClient.Flow.GetSource(context.Background(), "tutorial", "hello_world")
+			return resp, err
+		}, 0, nil},
+		{"Search should return transport errors", func() (*Response, error) {
+			_, resp, err := closedClient.Flow.Search(context.Background(), nil)
+			return resp, err
+		}, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.call()
+			if err == nil {
+				t.Fatalf("error = nil, want error")
+			}
+			if tt.sentinel != nil && !errors.Is(err, tt.sentinel) {
+				t.Errorf("error = %v, want %v", err, tt.sentinel)
+			}
+			if tt.code == 0 && resp != nil {
+				t.Errorf("Response got = %v, want nil", resp)
+			}
+			if tt.code != 0 && (resp == nil || resp.StatusCode != tt.code) {
+				t.Errorf("Response got = %v, want status %d", resp, tt.code)
+			}
+		})
+	}
+}
+
+func TestFlowService_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/flows/tutorial/hello_world", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	testMux.HandleFunc("/api/v1/flows/tutorial/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	tests := []struct {
+		name    string
+		flowID  string
+		code    int
+		wantErr error
+	}{
+		{"should delete Hello World", "hello_world", 204, nil},
+		{"should return not found", "missing", 404, ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := testClient.Flow.Delete(context.Background(), "tutorial", tt.flowID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Delete() error = %v, want %v", err, tt.wantErr)
+			}
+			if resp.StatusCode != tt.code {
+				t.Errorf("StatusCode got = %v, want %v", resp.StatusCode, tt.code)
+			}
+		})
+	}
+}
+
+func TestFlowService_DeleteByIDs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	count := 0
+	requests := 0
+	testMux.HandleFunc("/api/v1/flows/delete/by-ids", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		if got, want := string(body), `[{"namespace":"tutorial","id":"hello_world"},{"namespace":"tutorial","id":"missing"}]`; got != want {
+			t.Errorf("Request body got = %v, want %v", got, want)
+		}
+		fmt.Fprintf(w, `{"count":%d}`, count)
+	})
+
+	refs := []FlowRef{{Namespace: "tutorial", ID: "hello_world"}, {Namespace: "tutorial", ID: "missing"}}
+	tests := []struct {
+		name  string
+		count int
+		want  FlowStatus
+	}{
+		{"should report every flow as deleted", 2, FlowDeleted},
+		{"should report every flow as missing", 0, FlowNotFound},
+		{"should report unknown outcomes", 1, FlowUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count = tt.count
+			got, resp, err := testClient.Flow.DeleteByIDs(context.Background(), refs)
+			if err != nil {
+				t.Fatalf("DeleteByIDs() error = %v", err)
+			}
+			want := &BulkResult{Count: tt.count, Outcomes: []FlowOutcome{{refs[0], tt.want}, {refs[1], tt.want}}}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("DeleteByIDs() got = %v, want %v", got, want)
+			}
+			if resp.Request.URL.Path != "/api/v1/flows/delete/by-ids" {
+				t.Errorf("Response got = %v, want the bulk response", resp.Request.URL)
+			}
+		})
+	}
+
+	requests = 0
+	got, resp, err := testClient.Flow.DeleteByIDs(context.Background(), nil)
+	if err != nil || resp != nil || got.Count != 0 || len(got.Outcomes) != 0 || requests != 0 {
+		t.Errorf("DeleteByIDs() of no flow got = %v, %v, %v with %d requests, want no request", got, resp, err, requests)
+	}
+}
+
+func TestFlowService_DeleteByQuery(t *testing.T) {
+	setup()
+	defer teardown()
+
+	results, count, deletes := `[{"id":"a","namespace":"tutorial"},{"id":"b","namespace":"tutorial.sub"}]`, 2, 0
+	testMux.HandleFunc("/api/v1/flows/search", func(w http.ResponseWriter, r *http.Request) {
+		testRequestParams(t, r, map[string]string{"namespace": "tutorial", "labels": "env:dev", "page": "1", "size": "50"})
+		fmt.Fprintf(w, `{"results":%s,"total":2}`, results)
+	})
+	testMux.HandleFunc("/api/v1/flows/delete/by-query", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		testRequestParams(t, r, map[string]string{"namespace": "tutorial", "labels": "env:dev"})
+		deletes++
+		fmt.Fprintf(w, `{"count":%d}`, count)
+	})
+	filter := &FlowFilter{Namespace: "tutorial", Labels: map[string]string{"env": "dev"}}
+
+	got, _, err := testClient.Flow.DeleteByQuery(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("DeleteByQuery() error = %v", err)
+	}
+	want := &BulkResult{Count: 2, Outcomes: []FlowOutcome{
+		{FlowRef{"tutorial", "a"}, FlowDeleted},
+		{FlowRef{"tutorial.sub", "b"}, FlowDeleted},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DeleteByQuery() got = %v, want %v", got, want)
+	}
+
+	// a flow deleted between the search and the bulk request
+	count = 1
+	got, _, err = testClient.Flow.DeleteByQuery(context.Background(), filter)
+	if err != nil || got.Outcomes[0].Status != FlowUnknown || got.Outcomes[1].Status != FlowUnknown {
+		t.Errorf("DeleteByQuery() got = %v, error = %v, want unknown outcomes", got, err)
+	}
+
+	results, deletes = `[]`, 0
+	got, _, err = testClient.Flow.DeleteByQuery(context.Background(), filter)
+	if err != nil || len(got.Outcomes) != 0 || deletes != 0 {
+		t.Errorf("DeleteByQuery() without match got = %v, error = %v, %d bulk requests, want none", got, err, deletes)
+	}
+
+	if _, _, err := testClient.Flow.DeleteByQuery(context.Background(), &FlowFilter{}); err == nil {
+		t.Errorf("DeleteByQuery() with an empty filter error = nil, want error")
+	}
+}
+
+func TestFlowFilter_addValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *FlowFilter
+		want   string
+	}{
+		{"nil filter", nil, ""},
+		{"query and namespace", &FlowFilter{Query: "hello", Namespace: "tutorial"}, "namespace=tutorial&q=hello"},
+		{"sorted labels", &FlowFilter{Labels: map[string]string{"team": "data", "env": "prod"}}, "labels=env%3Aprod&labels=team%3Adata"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := url.Values{}
+			tt.filter.addValues(params)
+			if got := params.Encode(); got != tt.want {
+				t.Errorf("addValues() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlow_RoundTrip(t *testing.T) {
+	data := `{
+		"id": "etl",
+		"namespace": "company.team",
+		"revision": 4,
+		"description": "Nightly ETL",
+		"labels": [{"key": "env", "value": "prod"}, {"key": "team", "value": "data"}],
+		"variables": {"bucket": "s3://data", "batch": 500},
+		"tasks": [
+			{"id": "download", "type": "io.kestra.plugin.core.http.Download", "uri": "https://example.com/data.csv", "timeout": "PT5M",
+				"retry": {"type": "constant", "interval": "PT10S", "maxAttempt": 3, "warningOnRetry": true}},
+			{"id": "parallel", "type": "io.kestra.plugin.core.flow.Parallel", "concurrent": 2, "tasks": [
+				{"id": "script", "type": "io.kestra.plugin.scripts.python.Script", "script": "print(1)", "allowFailure": true, "disabled": true}
+			]}
+		],
+		"errors": [{"id": "alert", "type": "io.kestra.plugin.core.log.Log", "message": "failed", "level": "ERROR"}],
+		"finally": [{"id": "cleanup", "type": "io.kestra.plugin.core.storage.PurgeCurrentExecutionFiles"}],
+		"triggers": [{"id": "nightly", "type": "io.kestra.plugin.core.trigger.Schedule", "cron": "0 2 * * *", "disabled": true}],
+		"pluginDefaults": [{"type": "io.kestra.plugin.core.log.Log", "forced": true, "values": {"level": "WARN"}}],
+		"concurrency": {"limit": 1, "behavior": "QUEUE"},
+		"retry": {"type": "exponential", "interval": "PT1S", "maxInterval": "PT1M", "delayFactor": 2.5, "behavior": "CREATE_NEW_EXECUTION"},
+		"disabled": true,
+		"deleted": false,
+		"outputs": [{"id": "rows", "type": "INT", "value": "{{ outputs.parallel.rows }}"}],
+		"sla": [{"id": "max", "type": "MAX_DURATION", "duration": "PT1H"}]
+	}`
+
+	var flow Flow
+	if err := json.Unmarshal([]byte(data), &flow); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if v, _ := flow.Labels.Get("team"); v != "data" {
+		t.Errorf("Labels.Get() = %q, want %q", v, "data")
+	}
+	if got := flow.Tasks[0].Properties["uri"]; got != "https://example.com/data.csv" {
+		t.Errorf("Properties[uri] = %v, want %v", got, "https://example.com/data.csv")
+	}
+	if got := flow.Tasks[0].Retry.MaxAttempt; got != 3 {
+		t.Errorf("Retry.MaxAttempt = %v, want %v", got, 3)
+	}
+	if got := flow.Tasks[1].Tasks[0]; !got.AllowFailure || !got.Disabled || got.Properties["script"] != "print(1)" {
+		t.Errorf("nested task = %+v", got)
+	}
+	if got := flow.Triggers[0].Properties["cron"]; got != "0 2 * * *" {
+		t.Errorf("Triggers[0].Properties[cron] = %v, want %v", got, "0 2 * * *")
+	}
+	if _, ok := flow.Extra["outputs"]; !ok {
+		t.Errorf("Extra = %v, want outputs", flow.Extra)
+	}
+
+	encoded, err := json.Marshal(flow)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var want, got interface{}
+	if err := decodeJSON([]byte(data), &want); err != nil {
+		t.Fatal(err)
+	}
+	if err := decodeJSON(encoded, &got); err != nil {
+		t.Fatal(err)
+	}
+	// false values are omitted when encoding
+	delete(want.(map[string]interface{}), "deleted")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Marshal() got = %s, want %s", encoded, data)
+	}
+}
+
+func TestLabels_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Labels
+	}{
+		{"list", `[{"key":"env","value":"prod"}]`, Labels{{Key: "env", Value: "prod"}}},
+		{"map", `{"team":"data","env":"prod","tier":1}`, Labels{{Key: "env", Value: "prod"}, {Key: "team", Value: "data"}, {Key: "tier", Value: "1"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Labels
+			if err := json.Unmarshal([]byte(tt.data), &got); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnmarshalJSON() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// UpsertResult is the result of Upsert.
+type UpsertResult struct {
+	// Flow is the created or updated flow, or the stored flow if it did not change.
+	Flow *Flow
+	// Action is SyncCreate, SyncUpdate or SyncUnchanged.
+	Action SyncAction
+}
+
+// NewRevision reports whether Upsert created the flow or a new revision of it.
+func (r *UpsertResult) NewRevision() bool {
+	return r.Action == SyncCreate || r.Action == SyncUpdate
+}
+
+// Upsert creates the flow of a YAML source, or updates it if its namespace and ID already exist.
+// The source is compared with the stored one first, so that upserting an unchanged flow makes no
+// request changing it. If another writer creates the flow between the lookup and the creation, which
+// Kestra rejects as an invalid entity (ErrUnprocessable), the flow is looked up again and updated.
+// The source must declare its namespace and ID. Errors are returned as for CreateFromYAML and Update.
+func (s *FlowService) Upsert(ctx context.Context, source string) (*UpsertResult, *Response, error) {
+	parsed, err := ParseFlowYAML(source)
+	if err != nil {
+		return nil, nil, err
+	}
+	if parsed.Namespace == "" || parsed.ID == "" {
+		return nil, nil, errors.New("kestra: Upsert requires a source declaring its namespace and id")
+	}
+
+	current, resp, err := s.getWithSource(ctx, parsed.Namespace, parsed.ID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if current == nil {
+		flow, createResp, createErr := s.CreateFromYAML(ctx, source)
+		if createErr == nil {
+			return &UpsertResult{Flow: flow, Action: SyncCreate}, createResp, nil
+		}
+		if !errors.Is(createErr, ErrUnprocessable) && !errors.Is(createErr, ErrConflict) {
+			return nil, createResp, createErr
+		}
+
+		// the flow may have been created in the meantime; otherwise the flow itself is invalid
+		current, resp, err = s.getWithSource(ctx, parsed.Namespace, parsed.ID)
+		if err != nil {
+			return nil, resp, err
+		}
+		if current == nil {
+			return nil, createResp, createErr
+		}
+	}
+
+	if strings.TrimSpace(current.Source) == strings.TrimSpace(source) {
+		return &UpsertResult{Flow: current, Action: SyncUnchanged}, resp, nil
+	}
+
+	flow, resp, err := s.Update(ctx, parsed.Namespace, parsed.ID, source)
+	if err != nil {
+		return nil, resp, err
+	}
+	// the server keeps the revision of a flow equal to the stored one
+	action := SyncUpdate
+	if flow.Revision == current.Revision {
+		action = SyncUnchanged
+	}
+	return &UpsertResult{Flow: flow, Action: action}, resp, nil
+}
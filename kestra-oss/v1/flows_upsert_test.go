@@ -0,0 +1,135 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFlowService_Upsert(t *testing.T) {
+	const (
+		created   = "id: created\nnamespace: tutorial\ntasks:\n  - id: log\n    type: io.kestra.plugin.core.log.Log\n"
+		unchanged = "id: unchanged\nnamespace: tutorial\ntasks:\n  - id: log\n    type: io.kestra.plugin.core.log.Log\n"
+		updated   = "id: updated\nnamespace: tutorial\ndescription: new\ntasks:\n  - id: log\n    type: io.kestra.plugin.core.log.Log\n"
+		same      = "id: same\nnamespace: tutorial\ntasks:\n  - id: log\n    type: io.kestra.plugin.core.log.Log # logs\n"
+		raced     = "id: raced\nnamespace: tutorial\ntasks:\n  - id: log\n    type: io.kestra.plugin.core.log.Log\n"
+	)
+
+	setup()
+	defer teardown()
+
+	var requests []string
+	record := func(r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.Header.Get("Content-Type")+" "+string(body))
+	}
+	testMux.HandleFunc("/api/v1/flows", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		record(r)
+		if strings.HasPrefix(requests[len(requests)-1], "POST /api/v1/flows application/x-yaml id: raced") {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"message":"Invalid entity: flow id already exists","_embedded":{"errors":[{"message":"Invalid entity: flow id already exists"}]}}`)
+			return
+		}
+		if strings.HasPrefix(requests[len(requests)-1], "POST /api/v1/flows application/x-yaml id: invalid") {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"message":"Invalid entity: flow.tasks: must not be empty"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"created","namespace":"tutorial","revision":1}`)
+	})
+	lookups := 0
+	testMux.HandleFunc("/api/v1/flows/tutorial/raced", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut:
+			record(r)
+			fmt.Fprint(w, `{"id":"raced","namespace":"tutorial","revision":2}`)
+		case lookups == 0:
+			// created by another writer right after this lookup
+			lookups++
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			fmt.Fprint(w, `{"id":"raced","namespace":"tutorial","revision":1,"source":"id: raced\nnamespace: tutorial\n"}`)
+		}
+	})
+	testMux.HandleFunc("/api/v1/flows/tutorial/unchanged", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprintf(w, `{"id":"unchanged","namespace":"tutorial","revision":3,"source":%q}`, unchanged)
+	})
+	testMux.HandleFunc("/api/v1/flows/tutorial/updated", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			record(r)
+			fmt.Fprint(w, `{"id":"updated","namespace":"tutorial","revision":4}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"updated","namespace":"tutorial","revision":3,"source":"id: updated\nnamespace: tutorial\ndescription: old\n"}`)
+	})
+	testMux.HandleFunc("/api/v1/flows/tutorial/same", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			record(r)
+			fmt.Fprint(w, `{"id":"same","namespace":"tutorial","revision":3}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"same","namespace":"tutorial","revision":3,"source":"id: same\nnamespace: tutorial\n"}`)
+	})
+
+	tests := []struct {
+		name         string
+		source       string
+		wantAction   SyncAction
+		wantRevision string
+		wantRequests []string
+	}{
+		{"should create a missing flow", created, SyncCreate, "1",
+			[]string{"POST /api/v1/flows application/x-yaml " + created}},
+		{"should not update an unchanged flow", unchanged, SyncUnchanged, "3", nil},
+		{"should update a changed flow", updated, SyncUpdate, "4",
+			[]string{"PUT /api/v1/flows/tutorial/updated application/x-yaml " + updated}},
+		{"should report a kept revision", same, SyncUnchanged, "3",
+			[]string{"PUT /api/v1/flows/tutorial/same application/x-yaml " + same}},
+		{"should update a flow created concurrently", raced, SyncUpdate, "2", []string{
+			"POST /api/v1/flows application/x-yaml " + raced,
+			"PUT /api/v1/flows/tutorial/raced application/x-yaml " + raced,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			got, _, err := testClient.Flow.Upsert(context.Background(), tt.source)
+			if err != nil {
+				t.Fatalf("Upsert() error = %v", err)
+			}
+			if got.Action != tt.wantAction || got.Flow.Revision.String() != tt.wantRevision {
+				t.Errorf("Upsert() got action %v, revision %v, want %v, %v", got.Action, got.Flow.Revision, tt.wantAction, tt.wantRevision)
+			}
+			if got.NewRevision() != (tt.wantAction != SyncUnchanged) {
+				t.Errorf("NewRevision() got = %v for %v", got.NewRevision(), got.Action)
+			}
+			if !reflect.DeepEqual(requests, tt.wantRequests) {
+				t.Errorf("requests = %q, want %q", requests, tt.wantRequests)
+			}
+		})
+	}
+
+	requests = nil
+	_, _, err := testClient.Flow.Upsert(context.Background(), "id: invalid\nnamespace: tutorial\n")
+	if !errors.Is(err, ErrUnprocessable) || len(requests) != 1 {
+		t.Errorf("Upsert() of an invalid flow error = %v, requests = %q, want ErrUnprocessable after a single create", err, requests)
+	}
+
+	for _, source := range []string{"id: a\ntasks: []", "namespace: tutorial\ntasks: []"} {
+		if _, _, err := testClient.Flow.Upsert(context.Background(), source); err == nil {
+			t.Errorf("Upsert() of a source without namespace or id error = nil, want error")
+		}
+	}
+
+	_, parseErr := ParseFlowYAML("id: [invalid")
+	if _, _, err := testClient.Flow.Upsert(context.Background(), "id: [invalid"); err == nil || err.Error() != parseErr.Error() {
+		t.Errorf("Upsert() of an invalid source error = %v, want %v", err, parseErr)
+	}
+}
@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// FlowValidation is the result of the server-side validation of a flow, a task or a trigger.
+type FlowValidation struct {
+	// Flow and Namespace identify the validated flow, when they could be read from its source.
+	Flow      string `json:"flow,omitempty" structs:"flow,omitempty"`
+	Namespace string `json:"namespace,omitempty" structs:"namespace,omitempty"`
+	// Index is the position of the flow among the validated sources.
+	Index int `json:"index" structs:"index"`
+	// Constraints are the constraint violations making the source invalid, one message per violation.
+	Constraints []string `json:"constraints,omitempty" structs:"constraints,omitempty"`
+	// Outdated reports that the flow is older than its stored revision.
+	Outdated bool `json:"outdated,omitempty" structs:"outdated,omitempty"`
+	// DeprecationPaths are the paths of the deprecated properties used by the source.
+	DeprecationPaths []string `json:"deprecationPaths,omitempty" structs:"deprecationPaths,omitempty"`
+	Warnings         []string `json:"warnings,omitempty" structs:"warnings,omitempty"`
+	Infos            []string `json:"infos,omitempty" structs:"infos,omitempty"`
+}
+
+type flowValidation FlowValidation
+
+// UnmarshalJSON decodes a validation result. Kestra sends the constraint violations as a single
+// string with one violation per line; a list of messages is accepted as well.
+func (v *FlowValidation) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*flowValidation
+		Constraints interface{} `json:"constraints"`
+	}{flowValidation: (*flowValidation)(v)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	v.Constraints = nil
+	switch c := aux.Constraints.(type) {
+	case string:
+		for _, line := range strings.Split(c, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				v.Constraints = append(v.Constraints, line)
+			}
+		}
+	case []interface{}:
+		for _, message := range c {
+			if s, ok := message.(string); ok && s != "" {
+				v.Constraints = append(v.Constraints, s)
+			}
+		}
+	}
+	return nil
+}
+
+// Valid reports whether the validated source has no constraint violation.
+// Deprecations, warnings and infos do not make a source invalid.
+func (v *FlowValidation) Valid() bool {
+	return len(v.Constraints) == 0
+}
+
+// Validate asks the server to validate the YAML sources of one or more flows, without creating them.
+// A result is returned for each source, in order. Invalid flows are reported in the results, not as an error.
+// Without any source, no request is sent and no result is returned. Each source must hold a single document.
+func (s *FlowService) Validate(ctx context.Context, sources ...string) ([]FlowValidation, *Response, error) {
+	if err := s.client.requireVersion("flow validation", flowValidationVersion); err != nil {
+		return nil, nil, err
+	}
+	if len(sources) == 0 {
+		return nil, nil, nil
+	}
+
+	content, err := joinYAMLDocuments(sources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	apiEndpoint := s.client.apiPath(ctx, "flows/validate")
+	req, err := s.client.NewRequest(ctx, http.MethodPost, apiEndpoint, &content, "application/x-yaml")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var validations []FlowValidation
+	resp, err := s.client.Do(req, &validations)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return validations, resp, nil
+}
+
+// ValidateTask asks the server to validate the YAML source of a single task.
+func (s *FlowService) ValidateTask(ctx context.Context, source string) (*FlowValidation, *Response, error) {
+	return s.validateFragment(ctx, "TASKS", source)
+}
+
+// ValidateTrigger asks the server to validate the YAML source of a single trigger.
+func (s *FlowService) ValidateTrigger(ctx context.Context, source string) (*FlowValidation, *Response, error) {
+	return s.validateFragment(ctx, "TRIGGERS", source)
+}
+
+// validateFragment validates a task or a trigger, depending on section ("TASKS" or "TRIGGERS").
+func (s *FlowService) validateFragment(ctx context.Context, section string, source string) (*FlowValidation, *Response, error) {
+	if err := s.client.requireVersion("task and trigger validation", fragmentValidationVersion); err != nil {
+		return nil, nil, err
+	}
+
+	apiEndpoint := s.client.apiPath(ctx, "flows/validate/task") + "?section=" + section
+	req, err := s.client.NewRequest(ctx, http.MethodPost, apiEndpoint, &source, "application/x-yaml")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	validation := new(FlowValidation)
+	resp, err := s.client.Do(req, validation)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return validation, resp, nil
+}
@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestFlowService_Validate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/flows/validate", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		if got := r.Header.Get("Content-Type"); got != "application/x-yaml" {
+			t.Errorf("Content-Type got = %v, want application/x-yaml", got)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if got, want := string(body), "id: a\nnamespace: tutorial\n---\nid: b\nnamespace: tutorial\n"; got != want {
+			t.Errorf("Request body got = %q, want %q", got, want)
+		}
+
+		fmt.Fprint(w, `[
+			{"flow":"a","namespace":"tutorial","index":0,"outdated":true,"deprecationPaths":["tasks[0].format"],"warnings":["The task 'log' uses a deprecated property"],"infos":["Plugin io.kestra.plugin.core.log.Log is up to date"]},
+			{"flow":"b","namespace":"tutorial","index":1,"constraints":"tasks: must not be empty\nid: must match \"^[a-zA-Z0-9][a-zA-Z0-9._-]*\"\n"}
+		]`)
+	})
+
+	got, _, err := testClient.Flow.Validate(context.Background(), "id: a\nnamespace: tutorial\n", "\nid: b\nnamespace: tutorial")
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	want := []FlowValidation{
+		{Flow: "a", Namespace: "tutorial", Index: 0, Outdated: true,
+			DeprecationPaths: []string{"tasks[0].format"},
+			Warnings:         []string{"The task 'log' uses a deprecated property"},
+			Infos:            []string{"Plugin io.kestra.plugin.core.log.Log is up to date"}},
+		{Flow: "b", Namespace: "tutorial", Index: 1,
+			Constraints: []string{"tasks: must not be empty", `id: must match "^[a-zA-Z0-9][a-zA-Z0-9._-]*"`}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() got = %+v, want %+v", got, want)
+	}
+	if !got[0].Valid() || got[1].Valid() {
+		t.Errorf("Valid() got = %v, %v, want true, false", got[0].Valid(), got[1].Valid())
+	}
+}
+
+func TestFlowService_ValidateNoSources(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/flows/validate", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Validate() sent a request without any source")
+	})
+
+	got, resp, err := testClient.Flow.Validate(context.Background())
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if got != nil || resp != nil {
+		t.Errorf("Validate() got = %v, %v, want nil, nil", got, resp)
+	}
+}
+
+func TestFlowService_ValidateFragment(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/flows/validate/task", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		switch r.URL.Query().Get("section") {
+		case "TASKS":
+			fmt.Fprint(w, `{"index":0}`)
+		case "TRIGGERS":
+			fmt.Fprint(w, `{"index":0,"constraints":["cron: must be a valid cron expression"]}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	tests := []struct {
+		name string
+		call func() (*FlowValidation, *Response, error)
+		want *FlowValidation
+	}{
+		{"should validate a task", func() (*FlowValidation, *Response, error) {
+			return testClient.Flow.ValidateTask(context.Background(), "id: log\ntype: io.kestra.plugin.core.log.Log\nmessage: hi")
+		}, &FlowValidation{}},
+		{"should validate a trigger", func() (*FlowValidation, *Response, error) {
+			return testClient.Flow.ValidateTrigger(context.Background(), "id: nightly\ntype: io.kestra.plugin.core.trigger.Schedule\ncron: nope")
+		}, &FlowValidation{Constraints: []string{"cron: must be a valid cron expression"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := tt.call()
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package v1
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DOT renders the graph in the Graphviz DOT language, with a subgraph for each cluster.
+// It is turned into an image with e.g. dot -Tsvg.
+func (g *FlowGraph) DOT() string {
+	l := g.layout()
+
+	var sb strings.Builder
+	sb.WriteString("digraph {\n  node [shape=box];\n")
+	var write func(cluster int, indent string)
+	write = func(cluster int, indent string) {
+		for _, i := range l.nodes[cluster] {
+			node := g.Nodes[i]
+			switch {
+			case node.IsBoundary():
+				fmt.Fprintf(&sb, "%s%s [shape=point];\n", indent, dotQuote(node.UID))
+			case node.IsTrigger():
+				fmt.Fprintf(&sb, "%s%s [label=%s, shape=ellipse];\n", indent, dotQuote(node.UID), dotQuote(node.Label()))
+			default:
+				fmt.Fprintf(&sb, "%s%s [label=%s];\n", indent, dotQuote(node.UID), dotQuote(node.Label()))
+			}
+		}
+		for _, c := range l.children[cluster] {
+			// Graphviz only draws the subgraphs whose name starts with "cluster"
+			fmt.Fprintf(&sb, "%ssubgraph \"cluster_%d\" {\n", indent, c)
+			fmt.Fprintf(&sb, "%s  label=%s;\n", indent, dotQuote(g.Clusters[c].Cluster.Label()))
+			write(c, indent+"  ")
+			fmt.Fprintf(&sb, "%s}\n", indent)
+		}
+	}
+	write(-1, "  ")
+
+	for _, edge := range g.Edges {
+		source, target, ok := l.endpoints(edge)
+		if !ok {
+			continue
+		}
+		var attrs []string
+		if edge.Relation.Value != "" {
+			attrs = append(attrs, "label="+dotQuote(edge.Relation.Value))
+		}
+		if dashedRelation(edge.Relation.RelationType) {
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(&sb, "  %s -> %s", dotQuote(source), dotQuote(target))
+		if len(attrs) > 0 {
+			fmt.Fprintf(&sb, " [%s]", strings.Join(attrs, ", "))
+		}
+		sb.WriteString(";\n")
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart, with a subgraph for each cluster, e.g. to embed
+// it in Markdown.
+func (g *FlowGraph) Mermaid() string {
+	l := g.layout()
+
+	// Mermaid IDs cannot contain the dots of the UIDs
+	ids := make(map[string]string, len(g.Nodes))
+	for i, node := range g.Nodes {
+		ids[node.UID] = fmt.Sprintf("n%d", i)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("flowchart TD\n")
+	var write func(cluster int, indent string)
+	write = func(cluster int, indent string) {
+		for _, i := range l.nodes[cluster] {
+			node := g.Nodes[i]
+			switch {
+			case node.IsBoundary():
+				fmt.Fprintf(&sb, "%sn%d((\" \"))\n", indent, i)
+			case node.IsTrigger():
+				fmt.Fprintf(&sb, "%sn%d([%s])\n", indent, i, mermaidQuote(node.Label()))
+			default:
+				fmt.Fprintf(&sb, "%sn%d[%s]\n", indent, i, mermaidQuote(node.Label()))
+			}
+		}
+		for _, c := range l.children[cluster] {
+			fmt.Fprintf(&sb, "%ssubgraph c%d[%s]\n", indent, c, mermaidQuote(g.Clusters[c].Cluster.Label()))
+			write(c, indent+"  ")
+			fmt.Fprintf(&sb, "%send\n", indent)
+		}
+	}
+	write(-1, "  ")
+
+	for _, edge := range g.Edges {
+		source, target, ok := l.endpoints(edge)
+		if !ok {
+			continue
+		}
+		arrow := "-->"
+		if dashedRelation(edge.Relation.RelationType) {
+			arrow = "-.->"
+		}
+		if edge.Relation.Value != "" {
+			arrow += "|" + mermaidQuote(edge.Relation.Value) + "|"
+		}
+		fmt.Fprintf(&sb, "  %s %s %s\n", ids[source], arrow, ids[target])
+	}
+
+	return sb.String()
+}
+
+// graphLayout places the nodes and clusters of a FlowGraph in their innermost cluster.
+// The top level of the graph is the cluster -1.
+type graphLayout struct {
+	// nodes are the indexes of the nodes of each cluster, in the order of the graph
+	nodes map[int][]int
+	// children are the indexes of the clusters of each cluster, in the order of the graph
+	children map[int][]int
+	// clusters are the indexes of the clusters, by UID
+	clusters map[string]int
+	// known are the UIDs of the nodes
+	known map[string]bool
+	graph *FlowGraph
+}
+
+func (g *FlowGraph) layout() *graphLayout {
+	l := &graphLayout{
+		nodes:    make(map[int][]int),
+		children: make(map[int][]int),
+		clusters: make(map[string]int, len(g.Clusters)),
+		known:    make(map[string]bool, len(g.Nodes)),
+		graph:    g,
+	}
+	for c, cluster := range g.Clusters {
+		l.clusters[cluster.Cluster.UID] = c
+	}
+
+	// the innermost cluster is the one with the most parents
+	innermost := make(map[string]int)
+	for c, cluster := range g.Clusters {
+		for _, uid := range cluster.Nodes {
+			if current, ok := innermost[uid]; !ok || len(g.Clusters[current].Parents) < len(cluster.Parents) {
+				innermost[uid] = c
+			}
+		}
+	}
+
+	for c, cluster := range g.Clusters {
+		parent := -1
+		for _, uid := range cluster.Parents {
+			if p, ok := l.clusters[uid]; ok && p != c && (parent == -1 || len(g.Clusters[parent].Parents) < len(g.Clusters[p].Parents)) {
+				parent = p
+			}
+		}
+		l.children[parent] = append(l.children[parent], c)
+	}
+
+	for i, node := range g.Nodes {
+		if _, ok := l.clusters[node.UID]; ok {
+			continue
+		}
+		l.known[node.UID] = true
+		cluster, ok := innermost[node.UID]
+		if !ok {
+			cluster = -1
+		}
+		l.nodes[cluster] = append(l.nodes[cluster], i)
+	}
+	return l
+}
+
+// endpoints returns the UIDs of the nodes an edge links. An edge from a cluster leaves from its end node,
+// and an edge to a cluster goes to its start node. It returns false if a node is unknown.
+func (l *graphLayout) endpoints(edge GraphEdge) (string, string, bool) {
+	source, target := edge.Source, edge.Target
+	if c, ok := l.clusters[source]; ok {
+		source = l.graph.Clusters[c].End
+	}
+	if c, ok := l.clusters[target]; ok {
+		target = l.graph.Clusters[c].Start
+	}
+	return source, target, l.known[source] && l.known[target]
+}
+
+// dashedRelation reports whether the edges of a relation are drawn dashed, as they are not on the normal path.
+func dashedRelation(relation GraphRelationType) bool {
+	return relation == GraphError || relation == GraphFinally || relation == GraphAfterExecution
+}
+
+var dotReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// dotQuote returns s as a quoted DOT identifier.
+func dotQuote(s string) string {
+	return `"` + dotReplacer.Replace(s) + `"`
+}
+
+var mermaidReplacer = strings.NewReplacer(`"`, "#quot;", "\n", "<br>")
+
+// mermaidQuote returns s as a quoted Mermaid text.
+func mermaidQuote(s string) string {
+	return `"` + mermaidReplacer.Replace(s) + `"`
+}
@@ -0,0 +1,140 @@
+package v1
+
+import (
+	"testing"
+)
+
+// parallelGraph is the graph of a flow running two tasks in parallel, then a last task, with an error handler
+// and a schedule trigger.
+func parallelGraph() *FlowGraph {
+	const hierarchies = "io.kestra.core.models.hierarchies."
+	return &FlowGraph{
+		Nodes: []GraphNode{
+			{UID: "root.root", Type: hierarchies + "GraphClusterRoot"},
+			{UID: "root.Triggers.daily", Type: hierarchies + "GraphTrigger", Trigger: &FlowTrigger{ID: "daily"}},
+			{UID: "root.parallel.root", Type: hierarchies + "GraphClusterRoot"},
+			{UID: "root.parallel.a", Type: hierarchies + "GraphTask", Task: &FlowTask{ID: "a"}},
+			{UID: "root.parallel.b", Type: hierarchies + "GraphTask", Task: &FlowTask{ID: "b \"quoted\""}},
+			{UID: "root.parallel.end", Type: hierarchies + "GraphClusterEnd"},
+			{UID: "root.last", Type: hierarchies + "GraphTask", Task: &FlowTask{ID: "last"}},
+			{UID: "root.alert", Type: hierarchies + "GraphTask", Task: &FlowTask{ID: "alert"}},
+			{UID: "root.end", Type: hierarchies + "GraphClusterEnd"},
+		},
+		Edges: []GraphEdge{
+			{Source: "root.Triggers.daily", Target: "root.root"},
+			{Source: "root.root", Target: "root.parallel", Relation: GraphRelation{RelationType: GraphSequential}},
+			{Source: "root.parallel.root", Target: "root.parallel.a", Relation: GraphRelation{RelationType: GraphParallel}},
+			{Source: "root.parallel.root", Target: "root.parallel.b", Relation: GraphRelation{RelationType: GraphParallel}},
+			{Source: "root.parallel.a", Target: "root.parallel.end"},
+			{Source: "root.parallel.b", Target: "root.parallel.end"},
+			{Source: "root.parallel", Target: "root.last", Relation: GraphRelation{RelationType: GraphSequential}},
+			{Source: "root.last", Target: "root.end"},
+			{Source: "root.root", Target: "root.alert", Relation: GraphRelation{RelationType: GraphError, Value: "on error"}},
+			{Source: "root.alert", Target: "root.end"},
+			{Source: "root.missing", Target: "root.end"},
+		},
+		Clusters: []GraphCluster{
+			{
+				Cluster: GraphNode{UID: "root.Triggers", Type: hierarchies + "GraphCluster"},
+				Nodes:   []string{"root.Triggers.daily"},
+				Parents: []string{},
+			},
+			{
+				Cluster: GraphNode{UID: "root.parallel", Type: hierarchies + "GraphCluster", Task: &FlowTask{ID: "parallel"}},
+				Nodes:   []string{"root.parallel.root", "root.parallel.a", "root.parallel.b", "root.parallel.end"},
+				Parents: []string{},
+				Start:   "root.parallel.root",
+				End:     "root.parallel.end",
+			},
+		},
+	}
+}
+
+func TestFlowGraph_DOT(t *testing.T) {
+	want := `digraph {
+  node [shape=box];
+  "root.root" [shape=point];
+  "root.last" [label="last"];
+  "root.alert" [label="alert"];
+  "root.end" [shape=point];
+  subgraph "cluster_0" {
+    label="Triggers";
+    "root.Triggers.daily" [label="daily", shape=ellipse];
+  }
+  subgraph "cluster_1" {
+    label="parallel";
+    "root.parallel.root" [shape=point];
+    "root.parallel.a" [label="a"];
+    "root.parallel.b" [label="b \"quoted\""];
+    "root.parallel.end" [shape=point];
+  }
+  "root.Triggers.daily" -> "root.root";
+  "root.root" -> "root.parallel.root";
+  "root.parallel.root" -> "root.parallel.a";
+  "root.parallel.root" -> "root.parallel.b";
+  "root.parallel.a" -> "root.parallel.end";
+  "root.parallel.b" -> "root.parallel.end";
+  "root.parallel.end" -> "root.last";
+  "root.last" -> "root.end";
+  "root.root" -> "root.alert" [label="on error", style=dashed];
+  "root.alert" -> "root.end";
+}
+`
+	if got := parallelGraph().DOT(); got != want {
+		t.Errorf("DOT() got = %s, want %s", got, want)
+	}
+}
+
+func TestFlowGraph_Mermaid(t *testing.T) {
+	want := `flowchart TD
+  n0((" "))
+  n6["last"]
+  n7["alert"]
+  n8((" "))
+  subgraph c0["Triggers"]
+    n1(["daily"])
+  end
+  subgraph c1["parallel"]
+    n2((" "))
+    n3["a"]
+    n4["b #quot;quoted#quot;"]
+    n5((" "))
+  end
+  n1 --> n0
+  n0 --> n2
+  n2 --> n3
+  n2 --> n4
+  n3 --> n5
+  n4 --> n5
+  n5 --> n6
+  n6 --> n8
+  n0 -.->|"on error"| n7
+  n7 --> n8
+`
+	if got := parallelGraph().Mermaid(); got != want {
+		t.Errorf("Mermaid() got = %s, want %s", got, want)
+	}
+}
+
+func TestFlowGraph_nestedClusters(t *testing.T) {
+	graph := &FlowGraph{
+		Nodes: []GraphNode{
+			{UID: "root.outer.inner.log", Task: &FlowTask{ID: "log"}},
+		},
+		Clusters: []GraphCluster{
+			{Cluster: GraphNode{UID: "root.outer.inner", Task: &FlowTask{ID: "inner"}}, Nodes: []string{"root.outer.inner.log"}, Parents: []string{"root.outer"}},
+			{Cluster: GraphNode{UID: "root.outer", Task: &FlowTask{ID: "outer"}}, Nodes: []string{"root.outer.inner", "root.outer.inner.log"}, Parents: []string{}},
+		},
+	}
+
+	want := `flowchart TD
+  subgraph c1["outer"]
+    subgraph c0["inner"]
+      n0["log"]
+    end
+  end
+`
+	if got := graph.Mermaid(); got != want {
+		t.Errorf("Mermaid() got = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,487 @@
+package v1
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FlowInput is an input of a flow. The properties specific to a type are only set for the inputs of that
+// type, and properties not modeled by FlowInput are kept in Extra.
+type FlowInput struct {
+	ID   string `json:"id,omitempty" structs:"id,omitempty"`
+	Type string `json:"type,omitempty" structs:"type,omitempty"`
+	// Name is the ID of the input in flows written for Kestra versions before 0.15.
+	Name string `json:"name,omitempty" structs:"name,omitempty"`
+	// DisplayName is the label of the input in the UI.
+	DisplayName string `json:"displayName,omitempty" structs:"displayName,omitempty"`
+	Description string `json:"description,omitempty" structs:"description,omitempty"`
+	// Defaults is the default value of the input as decoded from JSON, e.g. a string, a json.Number, a bool
+	// or a []interface{}. Default returns it as the Go type of the input type.
+	Defaults interface{} `json:"defaults,omitempty" structs:"defaults,omitempty"`
+	// Required is nil for inputs not setting it, which Kestra makes required. Use IsRequired to read it.
+	Required *bool `json:"required,omitempty" structs:"required,omitempty"`
+	// DependsOn makes the input shown only once other inputs are set.
+	DependsOn *InputDependsOn `json:"dependsOn,omitempty" structs:"dependsOn,omitempty"`
+
+	// Validator is a regular expression STRING values must match.
+	Validator string `json:"validator,omitempty" structs:"validator,omitempty"`
+	// Values are the allowed values of ENUM, SELECT and MULTISELECT inputs.
+	Values []string `json:"values,omitempty" structs:"values,omitempty"`
+	// AllowCustomValue allows SELECT and MULTISELECT values other than Values.
+	AllowCustomValue bool `json:"allowCustomValue,omitempty" structs:"allowCustomValue,omitempty"`
+	// IsMultiselect makes a SELECT input take a list of values.
+	IsMultiselect bool `json:"isMultiselect,omitempty" structs:"isMultiselect,omitempty"`
+	// ItemType is the type of the items of ARRAY inputs, e.g. "INT".
+	ItemType string `json:"itemType,omitempty" structs:"itemType,omitempty"`
+	// Min and Max bound INT, FLOAT and DURATION values.
+	Min InputBound `json:"min,omitempty" structs:"min,omitempty"`
+	Max InputBound `json:"max,omitempty" structs:"max,omitempty"`
+	// After and Before bound DATETIME, DATE and TIME values.
+	After  string `json:"after,omitempty" structs:"after,omitempty"`
+	Before string `json:"before,omitempty" structs:"before,omitempty"`
+
+	// Extra holds the properties not modeled by FlowInput.
+	Extra map[string]interface{} `json:"-" structs:"-"`
+}
+
+type flowInput FlowInput
+
+// UnmarshalJSON decodes an input, keeping its unknown properties in Extra. Numbers in Defaults are
+// decoded as json.Number.
+func (i *FlowInput) UnmarshalJSON(data []byte) error {
+	*i = FlowInput{}
+	extra, err := unmarshalWithExtra(data, (*flowInput)(i))
+	if err != nil {
+		return err
+	}
+	i.Extra = extra
+
+	var defaults struct {
+		Defaults interface{} `json:"defaults"`
+	}
+	if err := decodeJSON(data, &defaults); err != nil {
+		return err
+	}
+	i.Defaults = defaults.Defaults
+	return nil
+}
+
+// MarshalJSON encodes an input with its Extra properties.
+func (i FlowInput) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(flowInput(i), i.Extra)
+}
+
+// InputDependsOn are the inputs an input depends on.
+type InputDependsOn struct {
+	Inputs []string `json:"inputs,omitempty" structs:"inputs,omitempty"`
+	// Condition is a Pebble expression that must be true for the input to be shown.
+	Condition string `json:"condition,omitempty" structs:"condition,omitempty"`
+}
+
+// IsRequired reports whether the input is required, which it is unless it sets required to false.
+func (i *FlowInput) IsRequired() bool {
+	return i.Required == nil || *i.Required
+}
+
+// HasDefault reports whether the input has a default value.
+func (i *FlowInput) HasDefault() bool {
+	return i.Defaults != nil
+}
+
+// Default returns the default value of the input as the Go type of its type, or nil if it has none:
+//   - a string for STRING, SECRET, EMAIL, URI, FILE, ENUM, YAML and single SELECT inputs;
+//   - an int64 for INT inputs, a float64 for FLOAT inputs and a bool for BOOLEAN and BOOL inputs;
+//   - a time.Time for DATETIME, DATE and TIME inputs, on January 1st of year 0 for TIME inputs;
+//   - a time.Duration for DURATION inputs;
+//   - a []string for MULTISELECT and multiple SELECT inputs;
+//   - a []interface{} of items converted according to ItemType for ARRAY inputs;
+//   - the decoded JSON value for JSON inputs, whose default may also be a JSON string.
+//
+// Defaults of other types are returned as is. An *InputError is returned if the default does not match
+// the type of the input.
+func (i *FlowInput) Default() (interface{}, error) {
+	if i.Defaults == nil {
+		return nil, nil
+	}
+	v, err := inputValue(i, i.Type, i.Defaults)
+	if err != nil {
+		return nil, &InputError{Input: i.ID, Message: "default " + err.Error()}
+	}
+	return v, nil
+}
+
+// DefaultString returns the default of a string input, or "" if it has none.
+func (i *FlowInput) DefaultString() (string, error) {
+	return defaultAs[string](i)
+}
+
+// DefaultInt returns the default of an INT input, or 0 if it has none.
+func (i *FlowInput) DefaultInt() (int64, error) {
+	return defaultAs[int64](i)
+}
+
+// DefaultFloat returns the default of a FLOAT input, or 0 if it has none.
+func (i *FlowInput) DefaultFloat() (float64, error) {
+	return defaultAs[float64](i)
+}
+
+// DefaultBool returns the default of a BOOLEAN input, or false if it has none.
+func (i *FlowInput) DefaultBool() (bool, error) {
+	return defaultAs[bool](i)
+}
+
+// DefaultTime returns the default of a DATETIME, DATE or TIME input, or the zero time if it has none.
+func (i *FlowInput) DefaultTime() (time.Time, error) {
+	return defaultAs[time.Time](i)
+}
+
+// DefaultDuration returns the default of a DURATION input, or 0 if it has none.
+func (i *FlowInput) DefaultDuration() (time.Duration, error) {
+	return defaultAs[time.Duration](i)
+}
+
+// DefaultStrings returns the default of a MULTISELECT or multiple SELECT input, or nil if it has none.
+func (i *FlowInput) DefaultStrings() ([]string, error) {
+	return defaultAs[[]string](i)
+}
+
+// defaultAs returns the default of input as a T, failing if the type of the input has another Go type.
+func defaultAs[T any](input *FlowInput) (T, error) {
+	var zero T
+	v, err := input.Default()
+	if v == nil || err != nil {
+		return zero, err
+	}
+	t, ok := v.(T)
+	if !ok {
+		return zero, &InputError{Input: input.ID, Message: fmt.Sprintf("default of a %s input is not a %T", input.Type, zero)}
+	}
+	return t, nil
+}
+
+// inputValue converts a value of type typ, decoded from JSON or set in Go, to the Go type documented by
+// FlowInput.Default. input gives the other properties of the type, such as ItemType.
+func inputValue(input *FlowInput, typ string, v interface{}) (interface{}, error) {
+	s, isString := v.(string)
+	switch strings.ToUpper(typ) {
+	case "STRING", "SECRET", "EMAIL", "URI", "FILE", "ENUM", "YAML":
+		if !isString {
+			return nil, fmt.Errorf("%s is not a string", inputText(v))
+		}
+		return s, nil
+	case "INT":
+		// numbers may be json.Number, Go numbers or strings; fmt.Sprint formats all of them as ParseInt expects
+		n, err := strconv.ParseInt(fmt.Sprint(v), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not an integer", inputText(v))
+		}
+		return n, nil
+	case "FLOAT":
+		f, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a number", inputText(v))
+		}
+		return f, nil
+	case "BOOLEAN", "BOOL":
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		if isString && (strings.EqualFold(s, "true") || strings.EqualFold(s, "false")) {
+			return strings.EqualFold(s, "true"), nil
+		}
+		return nil, fmt.Errorf("%s is not a boolean", inputText(v))
+	case "DATETIME":
+		return parseInputTime(v, time.RFC3339Nano)
+	case "DATE":
+		return parseInputTime(v, time.DateOnly)
+	case "TIME":
+		return parseInputTime(v, time.TimeOnly, "15:04")
+	case "DURATION":
+		d, err := parseISODuration(s)
+		if !isString || err != nil {
+			return nil, fmt.Errorf("%s is not an ISO 8601 duration", inputText(v))
+		}
+		return d, nil
+	case "JSON":
+		if !isString {
+			return v, nil
+		}
+		var decoded interface{}
+		if err := decodeJSON([]byte(s), &decoded); err != nil {
+			return nil, fmt.Errorf("%q is not valid JSON", s)
+		}
+		return decoded, nil
+	case "SELECT":
+		if isString && !input.IsMultiselect {
+			return s, nil
+		}
+		return inputStrings(v)
+	case "MULTISELECT":
+		return inputStrings(v)
+	case "ARRAY":
+		items, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s is not a list", inputText(v))
+		}
+		converted := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			if input.ItemType == "" {
+				converted = append(converted, item)
+				continue
+			}
+			c, err := inputValue(input, input.ItemType, item)
+			if err != nil {
+				return nil, err
+			}
+			converted = append(converted, c)
+		}
+		return converted, nil
+	}
+	return v, nil
+}
+
+// parseInputTime parses a string value with the first matching layout.
+func parseInputTime(v interface{}, layouts ...string) (time.Time, error) {
+	s, _ := v.(string)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s is not a %s", inputText(v), inputTimeKinds[layouts[0]])
+}
+
+var inputTimeKinds = map[string]string{time.RFC3339Nano: "date-time", time.DateOnly: "date", time.TimeOnly: "time"}
+
+// inputText formats a value in error messages, quoting strings.
+func inputText(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(v)
+}
+
+// inputStrings converts a list of strings, decoded from JSON or set in Go, to a []string.
+func inputStrings(v interface{}) ([]string, error) {
+	switch v := v.(type) {
+	case []string:
+		return v, nil
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s is not a list of strings", inputText(v))
+			}
+			values = append(values, s)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("%s is not a list of strings", inputText(v))
+}
+
+// InputBound is the Min or Max of an input: a number for INT and FLOAT inputs, or an ISO 8601 duration
+// such as "PT1H" for DURATION inputs. It decodes from both JSON numbers and strings.
+type InputBound string
+
+// UnmarshalJSON decodes a bound from a JSON number or string.
+func (b *InputBound) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = InputBound(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("kestra: input bound must be a number or a string, got %s", data)
+	}
+	*b = InputBound(n)
+	return nil
+}
+
+// MarshalJSON encodes a numeric bound as a JSON number and any other bound as a string.
+func (b InputBound) MarshalJSON() ([]byte, error) {
+	if _, err := strconv.ParseFloat(string(b), 64); err == nil {
+		return []byte(b), nil
+	}
+	return json.Marshal(string(b))
+}
+
+// InputError is a value of an execution input that Kestra would reject.
+type InputError struct {
+	// Input is the ID of the input.
+	Input   string
+	Message string
+}
+
+func (e *InputError) Error() string {
+	return fmt.Sprintf("kestra: input %q: %s", e.Input, e.Message)
+}
+
+// ValidateInputs checks the values of the inputs of an execution, as passed to ExecutionService.Create,
+// against the inputs of the flow: required inputs without defaults must have a value, and values must
+// parse as the type of their input and respect its Validator, Values, Min, Max, After and Before.
+// The values of ARRAY, MULTISELECT and multiple SELECT inputs are JSON lists, whose items must parse as
+// the ItemType of ARRAY inputs.
+// Every problem found is returned as an *InputError, joined in a single error. Values of undeclared
+// inputs are ignored, as Kestra does, and so are the constraints of types not listed in the Kestra
+// documentation of inputs. Inputs without an ID are looked up by their Name.
+func (f *Flow) ValidateInputs(values map[string]string) error {
+	var errs []error
+	for _, input := range f.Inputs {
+		id := input.key()
+		value, ok := values[id]
+		if !ok || value == "" {
+			if input.IsRequired() && !input.HasDefault() {
+				errs = append(errs, &InputError{Input: id, Message: "is required"})
+			}
+			continue
+		}
+		if msg := validateInput(&input, value); msg != "" {
+			errs = append(errs, &InputError{Input: id, Message: msg})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// key returns the ID of the input, or its Name in flows written before IDs replaced names.
+func (i *FlowInput) key() string {
+	if i.ID == "" {
+		return i.Name
+	}
+	return i.ID
+}
+
+// validateInput checks a value against the type and the constraints of input. It returns the problem
+// found, or "" if the value is valid.
+func validateInput(input *FlowInput, value string) string {
+	typ := strings.ToUpper(input.Type)
+	var v interface{} = value
+	// lists are sent as JSON
+	if typ == "ARRAY" || typ == "MULTISELECT" || (typ == "SELECT" && input.IsMultiselect) {
+		if err := decodeJSON([]byte(value), &v); err != nil {
+			return fmt.Sprintf("%q is not a JSON list", value)
+		}
+	}
+	native, err := inputValue(input, typ, v)
+	if err != nil {
+		return err.Error()
+	}
+
+	switch typ {
+	case "STRING":
+		if input.Validator == "" {
+			return ""
+		}
+		// the whole value must match, as with Java's String.matches
+		pattern, err := regexp.Compile(`^(?:` + input.Validator + `)$`)
+		if err != nil {
+			return fmt.Sprintf("invalid validator %q: %v", input.Validator, err)
+		}
+		if !pattern.MatchString(value) {
+			return fmt.Sprintf("%q must match %s", value, input.Validator)
+		}
+	case "INT":
+		return checkBounds(input, value, func(bound string) (int, error) {
+			b, err := strconv.ParseInt(bound, 10, 64)
+			return cmp.Compare(native.(int64), b), err
+		})
+	case "FLOAT":
+		return checkBounds(input, value, func(bound string) (int, error) {
+			b, err := strconv.ParseFloat(bound, 64)
+			return cmp.Compare(native.(float64), b), err
+		})
+	case "DURATION":
+		return checkBounds(input, value, func(bound string) (int, error) {
+			b, err := parseISODuration(bound)
+			return cmp.Compare(native.(time.Duration), b), err
+		})
+	case "DATETIME", "DATE", "TIME":
+		after := inputLimit{input.After, "after", "%s is before %s"}
+		before := inputLimit{input.Before, "before", "%s is after %s"}
+		return checkRange(value, after, before, func(bound string) (int, error) {
+			b, err := inputValue(input, typ, bound)
+			if err != nil {
+				return 0, err
+			}
+			return native.(time.Time).Compare(b.(time.Time)), nil
+		})
+	case "URI", "FILE":
+		// a FILE input passed as a form value is the URI of an uploaded or namespace file, e.g. kestra:///...
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" {
+			return fmt.Sprintf("%q is not an absolute URI", value)
+		}
+	case "ENUM", "SELECT", "MULTISELECT":
+		if typ != "ENUM" && input.AllowCustomValue {
+			return ""
+		}
+		values, ok := native.([]string)
+		if !ok {
+			values = []string{native.(string)}
+		}
+		for _, value := range values {
+			if !slices.Contains(input.Values, value) {
+				return fmt.Sprintf("%q is not one of %s", value, strings.Join(input.Values, ", "))
+			}
+		}
+	}
+	return ""
+}
+
+// checkBounds checks a value against the Min and Max of input. compare compares the value with a bound.
+func checkBounds(input *FlowInput, value string, compare func(bound string) (int, error)) string {
+	return checkRange(value,
+		inputLimit{string(input.Min), "min", "%s is less than the min %s"},
+		inputLimit{string(input.Max), "max", "%s is greater than the max %s"},
+		compare)
+}
+
+// inputLimit is a bound of checkRange. message formats the value and the bound when the value is out of range.
+type inputLimit struct {
+	bound, name, message string
+}
+
+// checkRange checks a value against its low and high limits, skipping the empty ones.
+func checkRange(value string, low, high inputLimit, compare func(bound string) (int, error)) string {
+	for i, limit := range []inputLimit{low, high} {
+		if limit.bound == "" {
+			continue
+		}
+		c, err := compare(limit.bound)
+		if err != nil {
+			return fmt.Sprintf("invalid %s %q", limit.name, limit.bound)
+		}
+		if (i == 0 && c < 0) || (i == 1 && c > 0) {
+			return fmt.Sprintf(limit.message, value, limit.bound)
+		}
+	}
+	return ""
+}
+
+// isoDurationPattern matches the ISO 8601 durations accepted by Java's Duration.parse, such as "PT1H30M"
+// or "P2DT0.5S".
+var isoDurationPattern = regexp.MustCompile(`^(?i)P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:[.,]\d{1,9})?)S)?)?$`)
+
+// parseISODuration parses an ISO 8601 duration made of days, hours, minutes and seconds.
+func parseISODuration(s string) (time.Duration, error) {
+	m := isoDurationPattern.FindStringSubmatch(s)
+	if m == nil || strings.HasSuffix(strings.ToUpper(s), "T") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	days, _ := strconv.Atoi("0" + m[1])
+	hours, _ := strconv.Atoi("0" + m[2])
+	minutes, _ := strconv.Atoi("0" + m[3])
+	seconds := "0"
+	if m[4] != "" {
+		seconds = strings.Replace(m[4], ",", ".", 1)
+	}
+	return time.ParseDuration(fmt.Sprintf("%dh%dm%ss", days*24+hours, minutes, seconds))
+}
@@ -0,0 +1,321 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFlowInput_JSON(t *testing.T) {
+	flow, err := ParseFlowYAML(`
+id: inputs
+namespace: tutorial
+inputs:
+  - id: count
+    type: INT
+    displayName: Count
+    defaults: 5
+    min: 1
+    max: 10
+  - id: ratio
+    type: FLOAT
+    defaults: 0.5
+    required: false
+  - id: dry_run
+    type: BOOLEAN
+    defaults: true
+    required: true
+  - id: config
+    type: JSON
+    defaults: {"retries": 3}
+  - id: regions
+    type: MULTISELECT
+    values: [eu, us]
+    defaults: [eu]
+    allowCustomValue: true
+  - id: ports
+    type: ARRAY
+    itemType: INT
+    defaults: [80, 443]
+  - id: region
+    type: SELECT
+    values: [eu, us]
+    dependsOn:
+      inputs: [regions]
+      condition: "{{ inputs.regions | length > 1 }}"
+  - id: data
+    type: FILE
+    allowedFileExtensions: [.csv]
+tasks:
+  - id: log
+    type: io.kestra.plugin.core.log.Log
+`)
+	if err != nil {
+		t.Fatalf("ParseFlowYAML() error = %v", err)
+	}
+
+	required, optional := true, false
+	want := []FlowInput{
+		{ID: "count", Type: "INT", DisplayName: "Count", Defaults: json.Number("5"), Min: "1", Max: "10"},
+		{ID: "ratio", Type: "FLOAT", Defaults: json.Number("0.5"), Required: &optional},
+		{ID: "dry_run", Type: "BOOLEAN", Defaults: true, Required: &required},
+		{ID: "config", Type: "JSON", Defaults: map[string]interface{}{"retries": json.Number("3")}},
+		{ID: "regions", Type: "MULTISELECT", Values: []string{"eu", "us"}, Defaults: []interface{}{"eu"}, AllowCustomValue: true},
+		{ID: "ports", Type: "ARRAY", ItemType: "INT", Defaults: []interface{}{json.Number("80"), json.Number("443")}},
+		{ID: "region", Type: "SELECT", Values: []string{"eu", "us"},
+			DependsOn: &InputDependsOn{Inputs: []string{"regions"}, Condition: "{{ inputs.regions | length > 1 }}"}},
+		{ID: "data", Type: "FILE", Extra: map[string]interface{}{"allowedFileExtensions": []interface{}{".csv"}}},
+	}
+	if !reflect.DeepEqual(flow.Inputs, want) {
+		t.Errorf("ParseFlowYAML() inputs got = %#v, want %#v", flow.Inputs, want)
+	}
+
+	data, err := json.Marshal(flow.Inputs)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded []FlowInput
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Errorf("inputs round trip got = %s", data)
+	}
+
+	// inputs are required unless they set required to false, which must survive encoding
+	for i, wantRequired := range []bool{true, false, true} {
+		if got := decoded[i].IsRequired(); got != wantRequired {
+			t.Errorf("IsRequired() of %q got = %v, want %v", decoded[i].ID, got, wantRequired)
+		}
+	}
+	source, err := MarshalFlowYAML(flow)
+	if err != nil {
+		t.Fatalf("MarshalFlowYAML() error = %v", err)
+	}
+	reparsed, err := ParseFlowYAML(source)
+	if err != nil {
+		t.Fatalf("ParseFlowYAML() error = %v", err)
+	}
+	if !reflect.DeepEqual(reparsed.Inputs, want) {
+		t.Errorf("inputs YAML round trip got = %s", source)
+	}
+}
+
+func TestFlowInput_Default(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   FlowInput
+		want    interface{}
+		wantErr bool
+	}{
+		{"should return no default", FlowInput{Type: "STRING"}, nil, false},
+		{"should return a string", FlowInput{Type: "STRING", Defaults: "world"}, "world", false},
+		{"should return an integer", FlowInput{Type: "INT", Defaults: json.Number("5")}, int64(5), false},
+		{"should return an integer set in Go", FlowInput{Type: "INT", Defaults: 5}, int64(5), false},
+		{"should return a float", FlowInput{Type: "FLOAT", Defaults: json.Number("0.5")}, 0.5, false},
+		{"should return a boolean", FlowInput{Type: "BOOL", Defaults: "true"}, true, false},
+		{"should return a date-time", FlowInput{Type: "DATETIME", Defaults: "2024-07-15T10:00:00Z"},
+			time.Date(2024, 7, 15, 10, 0, 0, 0, time.UTC), false},
+		{"should return a date", FlowInput{Type: "DATE", Defaults: "2024-07-15"}, time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC), false},
+		{"should return a time", FlowInput{Type: "TIME", Defaults: "10:30"}, time.Date(0, 1, 1, 10, 30, 0, 0, time.UTC), false},
+		{"should return a duration", FlowInput{Type: "DURATION", Defaults: "PT1H"}, time.Hour, false},
+		{"should decode a JSON string", FlowInput{Type: "JSON", Defaults: `{"a": 1}`}, map[string]interface{}{"a": json.Number("1")}, false},
+		{"should return a JSON value", FlowInput{Type: "JSON", Defaults: []interface{}{"a"}}, []interface{}{"a"}, false},
+		{"should return a selected value", FlowInput{Type: "SELECT", Defaults: "eu"}, "eu", false},
+		{"should return selected values", FlowInput{Type: "SELECT", IsMultiselect: true, Defaults: []interface{}{"eu"}}, []string{"eu"}, false},
+		{"should return converted items", FlowInput{Type: "ARRAY", ItemType: "INT", Defaults: []interface{}{json.Number("80")}}, []interface{}{int64(80)}, false},
+		{"should return items without item type", FlowInput{Type: "ARRAY", Defaults: []interface{}{"a"}}, []interface{}{"a"}, false},
+		{"should return other types as is", FlowInput{Type: "CUSTOM", Defaults: json.Number("1")}, json.Number("1"), false},
+		{"should fail on a mismatched type", FlowInput{Type: "INT", Defaults: true}, nil, true},
+		{"should fail on an invalid item", FlowInput{Type: "ARRAY", ItemType: "INT", Defaults: []interface{}{"a"}}, nil, true},
+		{"should fail on an invalid duration", FlowInput{Type: "DURATION", Defaults: "1h"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.input.Default()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Default() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var inputErr *InputError
+			if err != nil && !errors.As(err, &inputErr) {
+				t.Errorf("Default() error = %T, want *InputError", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Default() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	count := FlowInput{ID: "count", Type: "INT", Defaults: json.Number("5")}
+	if got, err := count.DefaultInt(); got != 5 || err != nil {
+		t.Errorf("DefaultInt() got = %v, %v, want 5", got, err)
+	}
+	if _, err := count.DefaultString(); err == nil {
+		t.Errorf("DefaultString() of an INT input error = nil, want error")
+	}
+	var none FlowInput
+	if got, err := none.DefaultDuration(); got != 0 || err != nil || none.HasDefault() {
+		t.Errorf("DefaultDuration() without default got = %v, %v, want 0", got, err)
+	}
+}
+
+func TestFlow_ValidateInputs(t *testing.T) {
+	// inputs are required unless they set required to false, as token
+	required, optional := true, false
+	flow := &Flow{Inputs: []FlowInput{
+		{ID: "name", Type: "STRING", Validator: "[a-z]+", Required: &optional},
+		{ID: "greeting", Type: "STRING", Required: &required, Defaults: "hello"},
+		{ID: "count", Type: "INT", Min: "1", Max: "10", Required: &optional},
+		{ID: "ratio", Type: "FLOAT", Max: "1.5", Required: &optional},
+		{ID: "dry_run", Type: "BOOLEAN", Required: &optional},
+		{ID: "start", Type: "DATETIME", After: "2024-01-01T00:00:00Z", Required: &optional},
+		{ID: "day", Type: "DATE", Before: "2024-12-31", Required: &optional},
+		{ID: "at", Type: "TIME", Required: &optional},
+		{ID: "timeout", Type: "DURATION", Min: "PT1M", Max: "P1D", Required: &optional},
+		{ID: "payload", Type: "JSON", Required: &optional},
+		{ID: "endpoint", Type: "URI", Required: &optional},
+		{ID: "data", Type: "FILE", Required: &optional},
+		{ID: "env", Type: "ENUM", Values: []string{"dev", "prod"}, Required: &optional},
+		{ID: "region", Type: "SELECT", Values: []string{"eu", "us"}, Required: &optional},
+		{ID: "token", Type: "SECRET"},
+		{ID: "regions", Type: "MULTISELECT", Values: []string{"eu", "us"}, Required: &optional},
+		{ID: "zone", Type: "SELECT", Values: []string{"eu-west-1"}, AllowCustomValue: true, Required: &optional},
+		{ID: "ports", Type: "ARRAY", ItemType: "INT", Required: &optional},
+		{Name: "retries", Type: "INT", Required: &optional},
+	}}
+
+	tests := []struct {
+		name   string
+		values map[string]string
+		want   []InputError
+	}{
+		{"should accept valid values", map[string]string{
+			"name": "world", "count": "10", "ratio": "0.5", "dry_run": "TRUE", "start": "2024-06-01T12:00:00+02:00",
+			"day": "2024-12-31", "at": "09:30", "timeout": "PT1H30M", "payload": `{"a": [1]}`, "endpoint": "https://kestra.io",
+			"data": "kestra:///tutorial/data.csv", "env": "prod", "region": "eu", "token": "secret",
+		}, nil},
+		{"should validate lists", map[string]string{"regions": `["eu", "asia"]`, "zone": "eu-west-3", "ports": `[80, "http"]`, "token": "secret"},
+			[]InputError{
+				{Input: "regions", Message: `"asia" is not one of eu, us`},
+				{Input: "ports", Message: `"http" is not an integer`},
+			}},
+		{"should report a value that is not a list", map[string]string{"regions": "eu", "token": "secret"}, []InputError{
+			{Input: "regions", Message: `"eu" is not a JSON list`},
+		}},
+		{"should look up inputs by name without an ID", map[string]string{"retries": "x", "token": "secret"}, []InputError{
+			{Input: "retries", Message: `"x" is not an integer`},
+		}},
+		{"should report a missing required input", map[string]string{"token": ""}, []InputError{
+			{Input: "token", Message: "is required"},
+		}},
+		{"should report every invalid value", map[string]string{
+			"name": "World", "count": "2.5", "ratio": "x", "dry_run": "yes", "start": "2024-06-01", "day": "31/12/2024",
+			"at": "9h", "timeout": "1h", "payload": "{", "endpoint": "/relative", "data": "data.csv", "env": "test",
+			"region": "asia", "token": "secret",
+		}, []InputError{
+			{Input: "name", Message: `"World" must match [a-z]+`},
+			{Input: "count", Message: `"2.5" is not an integer`},
+			{Input: "ratio", Message: `"x" is not a number`},
+			{Input: "dry_run", Message: `"yes" is not a boolean`},
+			{Input: "start", Message: `"2024-06-01" is not a date-time`},
+			{Input: "day", Message: `"31/12/2024" is not a date`},
+			{Input: "at", Message: `"9h" is not a time`},
+			{Input: "timeout", Message: `"1h" is not an ISO 8601 duration`},
+			{Input: "payload", Message: `"{" is not valid JSON`},
+			{Input: "endpoint", Message: `"/relative" is not an absolute URI`},
+			{Input: "data", Message: `"data.csv" is not an absolute URI`},
+			{Input: "env", Message: `"test" is not one of dev, prod`},
+			{Input: "region", Message: `"asia" is not one of eu, us`},
+		}},
+		{"should report values out of bounds", map[string]string{
+			"count": "0", "ratio": "2", "start": "2023-12-31T23:59:59Z", "day": "2025-01-01", "timeout": "P1DT1S", "token": "secret",
+		}, []InputError{
+			{Input: "count", Message: "0 is less than the min 1"},
+			{Input: "ratio", Message: "2 is greater than the max 1.5"},
+			{Input: "start", Message: "2023-12-31T23:59:59Z is before 2024-01-01T00:00:00Z"},
+			{Input: "day", Message: "2025-01-01 is after 2024-12-31"},
+			{Input: "timeout", Message: "P1DT1S is greater than the max P1D"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := flow.ValidateInputs(tt.values)
+
+			var got []InputError
+			if err != nil {
+				for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+					var inputErr *InputError
+					if !errors.As(e, &inputErr) {
+						t.Fatalf("ValidateInputs() returned %T, want *InputError", e)
+					}
+					got = append(got, *inputErr)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateInputs() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInputBound_JSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want InputBound
+	}{
+		{"should decode a number", `1.5`, "1.5"},
+		{"should decode a duration", `"PT1H"`, "PT1H"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got InputBound
+			if err := json.Unmarshal([]byte(tt.data), &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal() got = %q, want %q", got, tt.want)
+			}
+			data, err := json.Marshal(got)
+			if err != nil || string(data) != tt.data {
+				t.Errorf("Marshal() got = %s, %v, want %s", data, err, tt.data)
+			}
+		})
+	}
+
+	var bound InputBound
+	if err := json.Unmarshal([]byte(`[1]`), &bound); err == nil {
+		t.Errorf("Unmarshal() of a list error = nil, want error")
+	}
+}
+
+func TestParseISODuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"PT1H30M", "1h30m0s", false},
+		{"P2DT0.5S", "48h0m0.5s", false},
+		{"pt10s", "10s", false},
+		{"PT1,25S", "1.25s", false},
+		{"P", "", true},
+		{"PT", "", true},
+		{"P1DT", "", true},
+		{"PT1.5M", "", true},
+		{"1h", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseISODuration(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseISODuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("parseISODuration() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
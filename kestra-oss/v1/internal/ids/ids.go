@@ -0,0 +1,16 @@
+// Package ids holds the rules Kestra enforces on namespaces and on flow, task, trigger and input IDs,
+// shared by the flow builder of the v1 package and by flowlint.
+package ids
+
+import "regexp"
+
+// The patterns of namespaces, flow IDs, task and trigger IDs, and input IDs.
+var (
+	Namespace = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	Flow      = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+	Task      = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+	Input     = regexp.MustCompile(`^[a-zA-Z0-9][.a-zA-Z0-9_-]*$`)
+)
+
+// MaxLength is the maximum length of namespaces and IDs.
+const MaxLength = 100
@@ -0,0 +1,330 @@
+// Command mockgen generates the mocks of package kestramock from the service interfaces
+// declared in the v1 package. It is run by go generate:
+//
+//	go run ./internal/mockgen -in api.go -out kestramock/mocks.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// pkgName is the name the generated file uses to import the v1 package.
+	pkgName = "kestra"
+	pkgPath = "github.com/skeletonarmydev/go-kestra/kestra-oss/v1"
+)
+
+func main() {
+	in := flag.String("in", "api.go", "file declaring the interfaces to mock")
+	out := flag.String("out", "kestramock/mocks.go", "generated file")
+	flag.Parse()
+
+	src, err := os.ReadFile(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	code, err := generate(*in, src)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, code, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate returns the source of the mocks of every interface declared in src.
+func generate(filename string, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	imports := make(map[string]string) // name -> path
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = path
+	}
+
+	g := &generator{fset: fset, imports: imports, used: map[string]bool{}}
+	var body bytes.Buffer
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typ := spec.(*ast.TypeSpec)
+			iface, ok := typ.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+			if err := g.mock(&body, typ.Name.Name, iface); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by mockgen from %s. DO NOT EDIT.\n\n", filename)
+	buf.WriteString("package kestramock\n\nimport (\n")
+	paths := []string{}
+	for name := range g.used {
+		if name != pkgName {
+			paths = append(paths, strconv.Quote(imports[name]))
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		buf.WriteString("\t" + path + "\n")
+	}
+	fmt.Fprintf(&buf, "\n\t%s %q\n)\n", pkgName, pkgPath)
+	buf.Write(body.Bytes())
+
+	return format.Source(buf.Bytes())
+}
+
+type generator struct {
+	fset    *token.FileSet
+	imports map[string]string
+	used    map[string]bool // imported package names used by the generated code
+}
+
+// param is a parameter or result of a mocked method.
+type param struct {
+	name     string
+	typ      string
+	variadic bool
+	// zero is the value returned for a result when no Func is set, or empty for the zero value.
+	zero string
+}
+
+// mock writes the mock of the interface name.
+func (g *generator) mock(w *bytes.Buffer, name string, iface *ast.InterfaceType) error {
+	type method struct {
+		name    string
+		params  []param
+		results []param
+	}
+
+	var methods []method
+	for _, field := range iface.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			return fmt.Errorf("%s: embedded interfaces are not supported", name)
+		}
+		m := method{name: field.Names[0].Name}
+		m.params = g.params(fn.Params, "p")
+		m.results = g.params(fn.Results, "r")
+		methods = append(methods, m)
+	}
+
+	fmt.Fprintf(w, "\n// %s is a mock of kestra.%s recording its calls.\n", name, name)
+	fmt.Fprintf(w, "// Each method calls the matching Func field when set, and returns zero values otherwise.\n")
+	fmt.Fprintf(w, "type %s struct {\n\trecorder\n\n", name)
+	for _, m := range methods {
+		fmt.Fprintf(w, "\t%sFunc func(%s) %s\n", m.name, signature(m.params), resultsSignature(m.results))
+	}
+	fmt.Fprintf(w, "}\n\nvar _ %s.%s = (*%s)(nil)\n", pkgName, name, name)
+	g.used[pkgName] = true
+
+	for _, m := range methods {
+		args := make([]string, len(m.params))
+		for i, p := range m.params {
+			args[i] = p.name
+		}
+		call := strings.Join(args, ", ")
+		if n := len(m.params); n > 0 && m.params[n-1].variadic {
+			call += "..."
+		}
+
+		fmt.Fprintf(w, "\n// %s records the call and calls %sFunc.\n", m.name, m.name)
+		fmt.Fprintf(w, "func (m *%s) %s(%s) %s {\n", name, m.name, signature(m.params), resultsSignature(m.results))
+		fmt.Fprintf(w, "\tm.record(%s)\n", strings.Join(append([]string{strconv.Quote(m.name)}, args...), ", "))
+		fmt.Fprintf(w, "\tif m.%sFunc != nil {\n", m.name)
+		if len(m.results) > 0 {
+			fmt.Fprintf(w, "\t\treturn m.%sFunc(%s)\n\t}\n", m.name, call)
+		} else {
+			fmt.Fprintf(w, "\t\tm.%sFunc(%s)\n\t}\n", m.name, call)
+		}
+		if len(m.results) > 0 {
+			names := make([]string, len(m.results))
+			for i, r := range m.results {
+				if r.zero != "" {
+					fmt.Fprintf(w, "\tvar %s %s = %s\n", r.name, r.typ, r.zero)
+				} else {
+					fmt.Fprintf(w, "\tvar %s %s\n", r.name, r.typ)
+				}
+				names[i] = r.name
+			}
+			fmt.Fprintf(w, "\treturn %s\n", strings.Join(names, ", "))
+		}
+		fmt.Fprintf(w, "}\n")
+	}
+
+	return nil
+}
+
+// params returns the parameters of fields, naming unnamed ones with prefix.
+func (g *generator) params(fields *ast.FieldList, prefix string) []param {
+	if fields == nil {
+		return nil
+	}
+
+	var params []param
+	for _, field := range fields.List {
+		typ := field.Type
+		variadic := false
+		if ellipsis, ok := typ.(*ast.Ellipsis); ok {
+			typ, variadic = ellipsis.Elt, true
+		}
+		typeName := g.typeString(typ)
+		zero := g.zeroValue(typ)
+
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{nil}
+		}
+		for _, ident := range names {
+			name := fmt.Sprintf("%s%d", prefix, len(params))
+			if ident != nil && prefix == "p" && ident.Name != "m" && ident.Name != "_" {
+				name = ident.Name
+			}
+			params = append(params, param{name: name, typ: typeName, variadic: variadic, zero: zero})
+		}
+	}
+	return params
+}
+
+// typeString prints the type expression expr, qualifying the identifiers of the v1 package.
+func (g *generator) typeString(expr ast.Expr) string {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if pkg, ok := n.X.(*ast.Ident); ok {
+				g.used[pkg.Name] = true
+			}
+			return false
+		}
+		return true
+	})
+
+	qualified := qualify(expr)
+	var buf bytes.Buffer
+	printer.Fprint(&buf, g.fset, qualified)
+	if strings.Contains(buf.String(), pkgName+".") {
+		g.used[pkgName] = true
+	}
+	return buf.String()
+}
+
+// zeroValue returns the value a mock returns for a result of type expr when no Func is set.
+// Iterators are empty instead of nil, so that ranging over them does not panic.
+func (g *generator) zeroValue(expr ast.Expr) string {
+	var fn ast.Expr
+	var args []ast.Expr
+	switch e := expr.(type) {
+	case *ast.IndexExpr:
+		fn, args = e.X, []ast.Expr{e.Index}
+	case *ast.IndexListExpr:
+		fn, args = e.X, e.Indices
+	default:
+		return ""
+	}
+
+	sel, ok := fn.(*ast.SelectorExpr)
+	if !ok || g.imports[fmt.Sprint(sel.X)] != "iter" || !strings.HasPrefix(sel.Sel.Name, "Seq") {
+		return ""
+	}
+	types := make([]string, len(args))
+	for i, arg := range args {
+		types[i] = g.typeString(arg)
+	}
+	return "func(func(" + strings.Join(types, ", ") + ") bool) {}"
+}
+
+// qualify returns a copy of expr where the exported identifiers declared in the v1 package
+// are replaced by selectors on the package.
+func qualify(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if ast.IsExported(e.Name) {
+			return &ast.SelectorExpr{X: ast.NewIdent(pkgName), Sel: ast.NewIdent(e.Name)}
+		}
+		return e
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(e.X)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: e.Len, Elt: qualify(e.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(e.Key), Value: qualify(e.Value)}
+	case *ast.ChanType:
+		return &ast.ChanType{Dir: e.Dir, Value: qualify(e.Value)}
+	case *ast.IndexExpr:
+		return &ast.IndexExpr{X: qualify(e.X), Index: qualify(e.Index)}
+	case *ast.IndexListExpr:
+		indices := make([]ast.Expr, len(e.Indices))
+		for i, index := range e.Indices {
+			indices[i] = qualify(index)
+		}
+		return &ast.IndexListExpr{X: qualify(e.X), Indices: indices}
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: qualify(e.Elt)}
+	case *ast.FuncType:
+		return &ast.FuncType{Params: qualifyFields(e.Params), Results: qualifyFields(e.Results)}
+	}
+	return expr
+}
+
+func qualifyFields(fields *ast.FieldList) *ast.FieldList {
+	if fields == nil {
+		return nil
+	}
+	list := &ast.FieldList{}
+	for _, field := range fields.List {
+		list.List = append(list.List, &ast.Field{Names: field.Names, Type: qualify(field.Type)})
+	}
+	return list
+}
+
+func signature(params []param) string {
+	parts := make([]string, len(params))
+	for i, p := range params {
+		if p.variadic {
+			parts[i] = p.name + " ..." + p.typ
+		} else {
+			parts[i] = p.name + " " + p.typ
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func resultsSignature(results []param) string {
+	switch len(results) {
+	case 0:
+		return ""
+	case 1:
+		return results[0].typ
+	}
+	types := make([]string, len(results))
+	for i, r := range results {
+		types[i] = r.typ
+	}
+	return "(" + strings.Join(types, ", ") + ")"
+}
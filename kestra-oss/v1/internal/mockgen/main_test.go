@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	src, err := os.ReadFile("../../api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("../../kestramock/mocks.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := generate("api.go", src)
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("kestramock/mocks.go is out of date, run go generate in the v1 package directory")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	src := `package v1
+
+import "context"
+
+type ThingAPI interface {
+	Do(ctx context.Context, m string, names ...string) error
+	Ping()
+	List(context.Context) ([]Thing, map[string]*Thing)
+}
+`
+	got, err := generate("things.go", []byte(src))
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`func (m *ThingAPI) Do(ctx context.Context, p1 string, names ...string) error {`,
+		`return m.DoFunc(ctx, p1, names...)`,
+		`func (m *ThingAPI) Ping() {`,
+		`m.record("Ping")`,
+		`func (m *ThingAPI) List(p0 context.Context) ([]kestra.Thing, map[string]*kestra.Thing) {`,
+		`var _ kestra.ThingAPI = (*ThingAPI)(nil)`,
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("generate() does not contain %q:\n%s", want, got)
+		}
+	}
+}
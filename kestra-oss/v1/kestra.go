@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -24,6 +25,9 @@ type Client struct {
 	// User agent used when communicating with the Kestra API.
 	UserAgent string
 
+	// Headers added to every request, such as the Authorization header set by WithBasicAuth.
+	headers http.Header
+
 	// Reuse a single struct instead of allocating one for each service on the heap.
 	common service
 
@@ -31,9 +35,22 @@ type Client struct {
 	// BaseURL should always be specified with a trailing slash.
 	BaseURL *url.URL
 
-	Flow      *FlowService
-	Execution *ExecutionService
-	Log       *LogService
+	// Tenant routes every request through the tenant-aware API (/api/v1/{tenant}/...) of newer Kestra versions,
+	// e.g. "main". Requests use the legacy routes (/api/v1/...) when empty.
+	// It can be overridden per call with ContextWithTenant.
+	Tenant string
+
+	serverInfoMu sync.Mutex  // serverInfoMu protects serverInfo.
+	serverInfo   *ServerInfo // Server info cached by ServerInfo.
+
+	// RetryPolicy used to retry requests failing with a transient error. Retries are disabled when nil.
+	RetryPolicy *RetryPolicy
+
+	// Services used to talk to the different parts of the Kestra API.
+	// They are the concrete services of this package unless replaced with WithFlowAPI, WithExecutionAPI or WithLogAPI.
+	Flow      FlowAPI
+	Execution ExecutionAPI
+	Log       LogAPI
 }
 
 // service is the base structure to bundle API services
@@ -51,13 +68,17 @@ func (c *Client) Client() *http.Client {
 }
 
 // NewClient returns a new Kestra API client with provided base URL
-// If a nil httpClient is provided, a new http.Client will be used.
-// To use API methods which require authentication, provide an http.Client that will perform the authentication for you (such as that provided by the golang.org/x/oauth2 library).
+// If a nil httpClient is provided, a new http.Client will be used, otherwise a copy of httpClient is used.
+// To use API methods which require authentication, use one of the authentication options
+// (WithBasicAuth, WithBearerToken, WithAPIToken), or provide an http.Client that will perform the authentication
+// for you (such as that provided by the golang.org/x/oauth2 library).
 // baseURL is the HTTP endpoint of your Kestra instance and should always be specified with a trailing slash.
-func NewClient(baseURL string, httpClient *http.Client) (*Client, error) {
+func NewClient(baseURL string, httpClient *http.Client, opts ...ClientOption) (*Client, error) {
 	if httpClient == nil {
 		httpClient = &http.Client{}
 	}
+	// options such as WithTimeout modify the client, which must not affect the caller's one
+	clientCopy := *httpClient
 
 	baseEndpoint, err := url.Parse(baseURL)
 	if err != nil {
@@ -69,9 +90,10 @@ func NewClient(baseURL string, httpClient *http.Client) (*Client, error) {
 	}
 
 	c := &Client{
-		client:    httpClient,
+		client:    &clientCopy,
 		BaseURL:   baseEndpoint,
 		UserAgent: defaultUserAgent,
+		headers:   make(http.Header),
 	}
 	c.common.client = c
 
@@ -79,10 +101,54 @@ func NewClient(baseURL string, httpClient *http.Client) (*Client, error) {
 	c.Execution = (*ExecutionService)(&c.common)
 	c.Log = (*LogService)(&c.common)
 
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
 	return c, nil
 }
 
+type tenantContextKey struct{}
+
+// ContextWithTenant returns a copy of ctx routing the requests made with it through tenant,
+// overriding Client.Tenant. An empty tenant forces the legacy routes.
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// tenant returns the tenant requests made with ctx are routed through.
+func (c *Client) tenant(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantContextKey{}).(string); ok {
+		return tenant
+	}
+	return c.Tenant
+}
+
+// apiPath returns the path of an API endpoint, formatted from a path relative to the API root
+// such as "flows/%s/%s", and routed through the tenant of ctx or of the client when one is set.
+// String arguments are escaped, so that an ID holding a '/', '?' or '#' cannot change the route.
+func (c *Client) apiPath(ctx context.Context, format string, a ...interface{}) string {
+	args := make([]interface{}, len(a))
+	for i, arg := range a {
+		if s, ok := arg.(string); ok {
+			arg = url.PathEscape(s)
+		}
+		args[i] = arg
+	}
+	path := fmt.Sprintf(format, args...)
+	if tenant := c.tenant(ctx); tenant != "" {
+		return "/api/v1/" + url.PathEscape(tenant) + "/" + path
+	}
+	return "/api/v1/" + path
+}
+
 func (c *Client) NewRequest(ctx context.Context, method, urlStr string, body *string, content_type string) (*http.Request, error) {
+	if err := c.checkRoutes(ctx); err != nil {
+		return nil, err
+	}
+
 	rel, err := url.Parse(urlStr)
 	if err != nil {
 		return nil, err
@@ -90,6 +156,7 @@ func (c *Client) NewRequest(ctx context.Context, method, urlStr string, body *st
 
 	// Relative URLs should be specified without a preceding slash since BaseURL will have the trailing slash
 	rel.Path = strings.TrimLeft(rel.Path, "/")
+	rel.RawPath = strings.TrimLeft(rel.RawPath, "/")
 
 	u := c.BaseURL.ResolveReference(rel)
 
@@ -108,12 +175,25 @@ func (c *Client) NewRequest(ctx context.Context, method, urlStr string, body *st
 	} else {
 		req.Header.Set("Content-Type", content_type)
 	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	for key, values := range c.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
 
 	return req, nil
 }
 
+// Do sends an API request and returns the API response.
+// The API response is JSON decoded and stored in the value pointed to by v, or written as is to v
+// if it implements io.Writer.
+// Transport errors, non-2xx responses (as *APIError) and decoding errors are all returned.
+// When an error comes from the API or from decoding, the response is returned as well
+// so that the caller can inspect it further. An empty response body is not an error.
+// Transient failures are retried according to c.RetryPolicy.
 func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
-	httpResp, err := c.client.Do(req)
+	httpResp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
@@ -125,23 +205,32 @@ func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
 		return newResponse(httpResp, nil), err
 	}
 
-	if v != nil {
-		// Open a NewDecoder and defer closing the reader only if there is a provided interface to decode to
-		defer httpResp.Body.Close()
+	defer httpResp.Body.Close()
+
+	switch v := v.(type) {
+	case nil:
+	case io.Writer:
+		_, err = io.Copy(v, httpResp.Body)
+	default:
 		err = json.NewDecoder(httpResp.Body).Decode(v)
+		if err == io.EOF {
+			err = nil // ignore EOF errors caused by empty response body
+		}
 	}
 
 	resp := newResponse(httpResp, v)
 	return resp, err
 }
 
+// CheckResponse checks the API response for errors, and returns them if present.
+// A response is considered an error if it has a status code outside the 200 range.
+// The returned error is an *APIError holding the message and constraint violations sent by Kestra.
 func CheckResponse(r *http.Response) error {
 	if c := r.StatusCode; 200 <= c && c <= 299 {
 		return nil
 	}
 
-	err := fmt.Errorf("request failed. Please analyze the request body for more details. Status code: %d", r.StatusCode)
-	return err
+	return newAPIError(r)
 }
 
 // Response represents Kestra API response. It wraps http.Response returned from
@@ -149,9 +238,12 @@ func CheckResponse(r *http.Response) error {
 type Response struct {
 	*http.Response
 
-	StartAt    int
+	// StartAt is the offset of the first result of the page.
+	StartAt int
+	// MaxResults is the size of the requested page.
 	MaxResults int
-	Total      int
+	// Total is the number of results across all pages.
+	Total int
 }
 
 func newResponse(r *http.Response, v interface{}) *Response {
@@ -160,6 +252,27 @@ func newResponse(r *http.Response, v interface{}) *Response {
 	return resp
 }
 
+// populatePageValues sets the paging values of the response from the paging
+// parameters of the request and the total of the decoded PagedResults, if any.
 func (r *Response) populatePageValues(v interface{}) {
+	if paged, ok := v.(pagedResults); ok {
+		r.Total = paged.total()
+	}
+
+	if r.Response == nil || r.Request == nil || r.Request.URL == nil {
+		return
+	}
+
+	query := r.Request.URL.Query()
+	size, _ := strconv.Atoi(query.Get("size"))
+	if size <= 0 {
+		return
+	}
+	page, _ := strconv.Atoi(query.Get("page"))
+	if page < 1 {
+		page = 1
+	}
 
+	r.MaxResults = size
+	r.StartAt = (page - 1) * size
 }
@@ -2,12 +2,13 @@ package v1
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"reflect"
 	"strings"
-	"sync"
 	"testing"
 )
 
@@ -79,7 +80,10 @@ func TestCheckResponse(t *testing.T) {
 		args    args
 		wantErr bool
 	}{
-		// TODO: Add test cases.
+		{"should accept 200", args{&http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(`{}`))}}, false},
+		{"should accept 204", args{&http.Response{StatusCode: 204, Body: http.NoBody}}, false},
+		{"should reject 404", args{&http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader(`{"message":"Not Found"}`))}}, true},
+		{"should reject 500 without body", args{&http.Response{StatusCode: 500, Body: http.NoBody}}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -92,7 +96,6 @@ func TestCheckResponse(t *testing.T) {
 
 func TestClient_Client(t *testing.T) {
 	type fields struct {
-		clientMu  sync.Mutex
 		client    *http.Client
 		UserAgent string
 		common    service
@@ -110,7 +113,6 @@ func TestClient_Client(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &Client{
-				clientMu:  tt.fields.clientMu,
 				client:    tt.fields.client,
 				UserAgent: tt.fields.UserAgent,
 				common:    tt.fields.common,
@@ -127,7 +129,6 @@ func TestClient_Client(t *testing.T) {
 
 func TestClient_Do(t *testing.T) {
 	type fields struct {
-		clientMu  sync.Mutex
 		client    *http.Client
 		UserAgent string
 		common    service
@@ -151,7 +152,6 @@ func TestClient_Do(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &Client{
-				clientMu:  tt.fields.clientMu,
 				client:    tt.fields.client,
 				UserAgent: tt.fields.UserAgent,
 				common:    tt.fields.common,
@@ -173,7 +173,6 @@ func TestClient_Do(t *testing.T) {
 
 func TestClient_NewRequest(t *testing.T) {
 	type fields struct {
-		clientMu  sync.Mutex
 		client    *http.Client
 		UserAgent string
 		common    service
@@ -200,7 +199,6 @@ func TestClient_NewRequest(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &Client{
-				clientMu:  tt.fields.clientMu,
 				client:    tt.fields.client,
 				UserAgent: tt.fields.UserAgent,
 				common:    tt.fields.common,
@@ -231,7 +229,7 @@ func TestNewClient(t *testing.T) {
 		want    *Client
 		wantErr bool
 	}{
-		// TODO: Add test cases.
+		{"should reject an invalid URL", args{"://localhost", nil}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -297,3 +295,79 @@ func Test_newResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_apiPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		tenant string
+		ctx    context.Context
+		want   string
+	}{
+		{"legacy routes", "", context.Background(), "/api/v1/flows/tutorial/hello_world"},
+		{"client tenant", "main", context.Background(), "/api/v1/main/flows/tutorial/hello_world"},
+		{"context tenant", "", ContextWithTenant(context.Background(), "team a"), "/api/v1/team%20a/flows/tutorial/hello_world"},
+		{"context overrides client tenant", "main", ContextWithTenant(context.Background(), "other"), "/api/v1/other/flows/tutorial/hello_world"},
+		{"context forces legacy routes", "main", ContextWithTenant(context.Background(), ""), "/api/v1/flows/tutorial/hello_world"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := NewClient(testKestraInstanceURL, nil, WithTenant(tt.tenant))
+			if got := c.apiPath(tt.ctx, "flows/%s/%s", "tutorial", "hello_world"); got != tt.want {
+				t.Errorf("apiPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_apiPathEscape(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var got string
+	testMux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.EscapedPath() + "?" + r.URL.RawQuery
+		fmt.Fprint(w, `{"id":"x","namespace":"tutorial"}`)
+	})
+
+	if _, _, err := testClient.Flow.Get(context.Background(), "tutorial", "../a/b?c#d"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if want := "/api/v1/flows/tutorial/..%2Fa%2Fb%3Fc%23d?"; got != want {
+		t.Errorf("requested %v, want %v", got, want)
+	}
+
+	if _, _, err := testClient.Flow.GetRevision(context.Background(), "tutorial", "a/b", 2); err != nil {
+		t.Fatalf("GetRevision() error = %v", err)
+	}
+	if want := "/api/v1/flows/tutorial/a%2Fb?source=true&revision=2"; got != want {
+		t.Errorf("requested %v, want %v", got, want)
+	}
+}
+
+func TestClient_Tenant(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/main/flows/tutorial/hello_world", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"id":"hello_world","namespace":"tutorial"}`)
+	})
+	testMux.HandleFunc("/api/v1/main/flows/search", func(w http.ResponseWriter, r *http.Request) {
+		testRequestParams(t, r, map[string]string{"q": "hello"})
+		fmt.Fprint(w, `{"results":[{"id":"hello_world","namespace":"tutorial"}],"total":1}`)
+	})
+	testClient.Tenant = "main"
+
+	flow, _, err := testClient.Flow.Get(context.Background(), "tutorial", "hello_world")
+	if err != nil || flow == nil {
+		t.Fatalf("Get() got = %v, error = %v", flow, err)
+	}
+	result, _, err := testClient.Flow.Search(context.Background(), &FlowSearchOptions{FlowFilter: FlowFilter{Query: "hello"}})
+	if err != nil || result == nil || result.Total != 1 {
+		t.Fatalf("Search() got = %v, error = %v", result, err)
+	}
+	flow, resp, err := testClient.Flow.Get(ContextWithTenant(context.Background(), ""), "tutorial", "hello_world")
+	if err != nil || flow != nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Get() on legacy routes got = %v, error = %v", flow, err)
+	}
+}
@@ -0,0 +1,96 @@
+// Package kestramock provides mock implementations of the service interfaces of the go-kestra
+// client (kestra.FlowAPI, kestra.ExecutionAPI and kestra.LogAPI), for unit tests that should not
+// need an HTTP server.
+//
+// Each mock records its calls and delegates to an optional function field per method:
+//
+//	client, mocks := kestramock.NewClient()
+//	mocks.Flow.GetFunc = func(ctx context.Context, namespace, flowID string) (*kestra.Flow, *kestra.Response, error) {
+//		return &kestra.Flow{ID: flowID, Namespace: namespace}, nil, nil
+//	}
+//	// ... exercise the code under test with client ...
+//	if len(mocks.Flow.CallsTo("Get")) != 1 { ... }
+//
+// The mocks are generated from the interfaces by go generate, in the v1 package directory.
+package kestramock
+
+import (
+	"slices"
+	"sync"
+
+	kestra "github.com/skeletonarmydev/go-kestra/kestra-oss/v1"
+)
+
+// Call is a call recorded by a mock.
+type Call struct {
+	// Method is the name of the called method, e.g. "Get".
+	Method string
+	// Args are the arguments of the call, including the context.
+	Args []interface{}
+}
+
+// recorder records the calls of a mock. It is safe for concurrent use.
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns every recorded call, in order.
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+// CallsTo returns the recorded calls of method.
+func (r *recorder) CallsTo(method string) []Call {
+	var calls []Call
+	for _, call := range r.Calls() {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls.
+func (r *recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// Mocks are the services of a client returned by NewClient.
+type Mocks struct {
+	Flow      *FlowAPI
+	Execution *ExecutionAPI
+	Log       *LogAPI
+}
+
+// NewClient returns a client whose services are new mocks, along with the mocks.
+// Additional options are applied after the mocks are set.
+func NewClient(opts ...kestra.ClientOption) (*kestra.Client, *Mocks) {
+	mocks := &Mocks{
+		Flow:      &FlowAPI{},
+		Execution: &ExecutionAPI{},
+		Log:       &LogAPI{},
+	}
+
+	opts = append([]kestra.ClientOption{
+		kestra.WithFlowAPI(mocks.Flow),
+		kestra.WithExecutionAPI(mocks.Execution),
+		kestra.WithLogAPI(mocks.Log),
+	}, opts...)
+
+	client, err := kestra.NewClient("http://localhost:8080/", nil, opts...)
+	if err != nil {
+		panic("kestramock: " + err.Error())
+	}
+	return client, mocks
+}
@@ -0,0 +1,52 @@
+package kestramock
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	kestra "github.com/skeletonarmydev/go-kestra/kestra-oss/v1"
+)
+
+func TestNewClient(t *testing.T) {
+	client, mocks := NewClient()
+	ctx := context.Background()
+
+	mocks.Flow.GetFunc = func(ctx context.Context, namespace string, flowID string) (*kestra.Flow, *kestra.Response, error) {
+		return &kestra.Flow{ID: flowID, Namespace: namespace}, nil, nil
+	}
+	mocks.Execution.CreateFunc = func(ctx context.Context, namespace string, flowId string, input map[string]string) (*kestra.Execution, *kestra.Response, error) {
+		return nil, nil, errors.New("boom")
+	}
+
+	flow, _, err := client.Flow.Get(ctx, "tutorial", "hello_world")
+	if err != nil || flow.ID != "hello_world" {
+		t.Errorf("Flow.Get() got = %v, error = %v", flow, err)
+	}
+	if _, _, err := client.Execution.Create(ctx, "tutorial", "hello_world", nil); err == nil {
+		t.Errorf("Execution.Create() error = nil, want error")
+	}
+
+	// methods without Func return zero values, and empty iterators
+	logs, _, err := client.Log.Get(ctx, "1CcnlV1DwvXXZauauyirIO")
+	if logs != nil || err != nil {
+		t.Errorf("Log.Get() got = %v, error = %v", logs, err)
+	}
+	for range client.Flow.SearchAll(ctx, nil) {
+		t.Errorf("SearchAll() yielded a value")
+	}
+
+	want := []Call{{Method: "Get", Args: []interface{}{ctx, "tutorial", "hello_world"}}}
+	if got := mocks.Flow.CallsTo("Get"); !reflect.DeepEqual(got, want) {
+		t.Errorf("CallsTo() got = %v, want %v", got, want)
+	}
+	if got := len(mocks.Flow.Calls()); got != 2 {
+		t.Errorf("Calls() got %d calls, want 2", got)
+	}
+
+	mocks.Flow.Reset()
+	if got := len(mocks.Flow.Calls()); got != 0 {
+		t.Errorf("Calls() after Reset() got %d calls, want 0", got)
+	}
+}
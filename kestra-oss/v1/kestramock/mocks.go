@@ -0,0 +1,459 @@
+// Code generated by mockgen from api.go. DO NOT EDIT.
+
+package kestramock
+
+import (
+	"context"
+	"io"
+	"iter"
+
+	kestra "github.com/skeletonarmydev/go-kestra/kestra-oss/v1"
+)
+
+// FlowAPI is a mock of kestra.FlowAPI recording its calls.
+// Each method calls the matching Func field when set, and returns zero values otherwise.
+type FlowAPI struct {
+	recorder
+
+	GetAllFunc             func(ctx context.Context, namespace string) (*[]kestra.Flow, *kestra.Response, error)
+	GetFunc                func(ctx context.Context, namespace string, flowID string) (*kestra.Flow, *kestra.Response, error)
+	GetSourceFunc          func(ctx context.Context, namespace string, flowID string) (string, *kestra.Response, error)
+	RevisionsFunc          func(ctx context.Context, namespace string, flowID string) ([]kestra.Flow, *kestra.Response, error)
+	GetRevisionFunc        func(ctx context.Context, namespace string, flowID string, revision int) (*kestra.Flow, *kestra.Response, error)
+	DependenciesFunc       func(ctx context.Context, namespace string, flowID string, transitive bool) (*kestra.DependencyGraph, *kestra.Response, error)
+	GraphFunc              func(ctx context.Context, namespace string, flowID string) (*kestra.FlowGraph, *kestra.Response, error)
+	SearchFunc             func(ctx context.Context, opts *kestra.FlowSearchOptions) (*kestra.SearchResult, *kestra.Response, error)
+	SearchAllFunc          func(ctx context.Context, opts *kestra.FlowSearchOptions) iter.Seq2[kestra.Flow, error]
+	CreateFunc             func(ctx context.Context, content string) (*kestra.Flow, *kestra.Response, error)
+	CreateFromYAMLFunc     func(ctx context.Context, source string) (*kestra.Flow, *kestra.Response, error)
+	CreateFromFlowFunc     func(ctx context.Context, flow *kestra.Flow) (*kestra.Flow, *kestra.Response, error)
+	UpdateFunc             func(ctx context.Context, namespace string, flowID string, content string) (*kestra.Flow, *kestra.Response, error)
+	UpsertFunc             func(ctx context.Context, source string) (*kestra.UpsertResult, *kestra.Response, error)
+	DeleteFunc             func(ctx context.Context, namespace string, flowID string) (*kestra.Response, error)
+	DeleteByIDsFunc        func(ctx context.Context, flows []kestra.FlowRef) (*kestra.BulkResult, *kestra.Response, error)
+	DeleteByQueryFunc      func(ctx context.Context, filter *kestra.FlowFilter) (*kestra.BulkResult, *kestra.Response, error)
+	EnableFunc             func(ctx context.Context, namespace string, flowID string) (*kestra.FlowOutcome, *kestra.Response, error)
+	DisableFunc            func(ctx context.Context, namespace string, flowID string) (*kestra.FlowOutcome, *kestra.Response, error)
+	SetDisabledByIDsFunc   func(ctx context.Context, flows []kestra.FlowRef, disabled bool) (*kestra.BulkResult, *kestra.Response, error)
+	SetDisabledByQueryFunc func(ctx context.Context, filter *kestra.FlowFilter, disabled bool) (*kestra.BulkResult, *kestra.Response, error)
+	ValidateFunc           func(ctx context.Context, sources ...string) ([]kestra.FlowValidation, *kestra.Response, error)
+	ValidateTaskFunc       func(ctx context.Context, source string) (*kestra.FlowValidation, *kestra.Response, error)
+	ValidateTriggerFunc    func(ctx context.Context, source string) (*kestra.FlowValidation, *kestra.Response, error)
+	ExportByQueryFunc      func(ctx context.Context, filter *kestra.FlowFilter, w io.Writer) (*kestra.Response, error)
+	ExportByIDsFunc        func(ctx context.Context, flows []kestra.FlowRef, w io.Writer) (*kestra.Response, error)
+	ImportFunc             func(ctx context.Context, r io.Reader) (*kestra.Response, error)
+	SyncNamespaceFunc      func(ctx context.Context, namespace string, sources []string, opts *kestra.SyncOptions) (*kestra.SyncResult, *kestra.Response, error)
+}
+
+var _ kestra.FlowAPI = (*FlowAPI)(nil)
+
+// GetAll records the call and calls GetAllFunc.
+func (m *FlowAPI) GetAll(ctx context.Context, namespace string) (*[]kestra.Flow, *kestra.Response, error) {
+	m.record("GetAll", ctx, namespace)
+	if m.GetAllFunc != nil {
+		return m.GetAllFunc(ctx, namespace)
+	}
+	var r0 *[]kestra.Flow
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// Get records the call and calls GetFunc.
+func (m *FlowAPI) Get(ctx context.Context, namespace string, flowID string) (*kestra.Flow, *kestra.Response, error) {
+	m.record("Get", ctx, namespace, flowID)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, namespace, flowID)
+	}
+	var r0 *kestra.Flow
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// GetSource records the call and calls GetSourceFunc.
+func (m *FlowAPI) GetSource(ctx context.Context, namespace string, flowID string) (string, *kestra.Response, error) {
+	m.record("GetSource", ctx, namespace, flowID)
+	if m.GetSourceFunc != nil {
+		return m.GetSourceFunc(ctx, namespace, flowID)
+	}
+	var r0 string
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// Revisions records the call and calls RevisionsFunc.
+func (m *FlowAPI) Revisions(ctx context.Context, namespace string, flowID string) ([]kestra.Flow, *kestra.Response, error) {
+	m.record("Revisions", ctx, namespace, flowID)
+	if m.RevisionsFunc != nil {
+		return m.RevisionsFunc(ctx, namespace, flowID)
+	}
+	var r0 []kestra.Flow
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// GetRevision records the call and calls GetRevisionFunc.
+func (m *FlowAPI) GetRevision(ctx context.Context, namespace string, flowID string, revision int) (*kestra.Flow, *kestra.Response, error) {
+	m.record("GetRevision", ctx, namespace, flowID, revision)
+	if m.GetRevisionFunc != nil {
+		return m.GetRevisionFunc(ctx, namespace, flowID, revision)
+	}
+	var r0 *kestra.Flow
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// Dependencies records the call and calls DependenciesFunc.
+func (m *FlowAPI) Dependencies(ctx context.Context, namespace string, flowID string, transitive bool) (*kestra.DependencyGraph, *kestra.Response, error) {
+	m.record("Dependencies", ctx, namespace, flowID, transitive)
+	if m.DependenciesFunc != nil {
+		return m.DependenciesFunc(ctx, namespace, flowID, transitive)
+	}
+	var r0 *kestra.DependencyGraph
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// Graph records the call and calls GraphFunc.
+func (m *FlowAPI) Graph(ctx context.Context, namespace string, flowID string) (*kestra.FlowGraph, *kestra.Response, error) {
+	m.record("Graph", ctx, namespace, flowID)
+	if m.GraphFunc != nil {
+		return m.GraphFunc(ctx, namespace, flowID)
+	}
+	var r0 *kestra.FlowGraph
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// Search records the call and calls SearchFunc.
+func (m *FlowAPI) Search(ctx context.Context, opts *kestra.FlowSearchOptions) (*kestra.SearchResult, *kestra.Response, error) {
+	m.record("Search", ctx, opts)
+	if m.SearchFunc != nil {
+		return m.SearchFunc(ctx, opts)
+	}
+	var r0 *kestra.SearchResult
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// SearchAll records the call and calls SearchAllFunc.
+func (m *FlowAPI) SearchAll(ctx context.Context, opts *kestra.FlowSearchOptions) iter.Seq2[kestra.Flow, error] {
+	m.record("SearchAll", ctx, opts)
+	if m.SearchAllFunc != nil {
+		return m.SearchAllFunc(ctx, opts)
+	}
+	var r0 iter.Seq2[kestra.Flow, error] = func(func(kestra.Flow, error) bool) {}
+	return r0
+}
+
+// Create records the call and calls CreateFunc.
+func (m *FlowAPI) Create(ctx context.Context, content string) (*kestra.Flow, *kestra.Response, error) {
+	m.record("Create", ctx, content)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, content)
+	}
+	var r0 *kestra.Flow
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// CreateFromYAML records the call and calls CreateFromYAMLFunc.
+func (m *FlowAPI) CreateFromYAML(ctx context.Context, source string) (*kestra.Flow, *kestra.Response, error) {
+	m.record("CreateFromYAML", ctx, source)
+	if m.CreateFromYAMLFunc != nil {
+		return m.CreateFromYAMLFunc(ctx, source)
+	}
+	var r0 *kestra.Flow
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// CreateFromFlow records the call and calls CreateFromFlowFunc.
+func (m *FlowAPI) CreateFromFlow(ctx context.Context, flow *kestra.Flow) (*kestra.Flow, *kestra.Response, error) {
+	m.record("CreateFromFlow", ctx, flow)
+	if m.CreateFromFlowFunc != nil {
+		return m.CreateFromFlowFunc(ctx, flow)
+	}
+	var r0 *kestra.Flow
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// Update records the call and calls UpdateFunc.
+func (m *FlowAPI) Update(ctx context.Context, namespace string, flowID string, content string) (*kestra.Flow, *kestra.Response, error) {
+	m.record("Update", ctx, namespace, flowID, content)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, namespace, flowID, content)
+	}
+	var r0 *kestra.Flow
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// Upsert records the call and calls UpsertFunc.
+func (m *FlowAPI) Upsert(ctx context.Context, source string) (*kestra.UpsertResult, *kestra.Response, error) {
+	m.record("Upsert", ctx, source)
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(ctx, source)
+	}
+	var r0 *kestra.UpsertResult
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// Delete records the call and calls DeleteFunc.
+func (m *FlowAPI) Delete(ctx context.Context, namespace string, flowID string) (*kestra.Response, error) {
+	m.record("Delete", ctx, namespace, flowID)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, namespace, flowID)
+	}
+	var r0 *kestra.Response
+	var r1 error
+	return r0, r1
+}
+
+// DeleteByIDs records the call and calls DeleteByIDsFunc.
+func (m *FlowAPI) DeleteByIDs(ctx context.Context, flows []kestra.FlowRef) (*kestra.BulkResult, *kestra.Response, error) {
+	m.record("DeleteByIDs", ctx, flows)
+	if m.DeleteByIDsFunc != nil {
+		return m.DeleteByIDsFunc(ctx, flows)
+	}
+	var r0 *kestra.BulkResult
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// DeleteByQuery records the call and calls DeleteByQueryFunc.
+func (m *FlowAPI) DeleteByQuery(ctx context.Context, filter *kestra.FlowFilter) (*kestra.BulkResult, *kestra.Response, error) {
+	m.record("DeleteByQuery", ctx, filter)
+	if m.DeleteByQueryFunc != nil {
+		return m.DeleteByQueryFunc(ctx, filter)
+	}
+	var r0 *kestra.BulkResult
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// Enable records the call and calls EnableFunc.
+func (m *FlowAPI) Enable(ctx context.Context, namespace string, flowID string) (*kestra.FlowOutcome, *kestra.Response, error) {
+	m.record("Enable", ctx, namespace, flowID)
+	if m.EnableFunc != nil {
+		return m.EnableFunc(ctx, namespace, flowID)
+	}
+	var r0 *kestra.FlowOutcome
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// Disable records the call and calls DisableFunc.
+func (m *FlowAPI) Disable(ctx context.Context, namespace string, flowID string) (*kestra.FlowOutcome, *kestra.Response, error) {
+	m.record("Disable", ctx, namespace, flowID)
+	if m.DisableFunc != nil {
+		return m.DisableFunc(ctx, namespace, flowID)
+	}
+	var r0 *kestra.FlowOutcome
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// SetDisabledByIDs records the call and calls SetDisabledByIDsFunc.
+func (m *FlowAPI) SetDisabledByIDs(ctx context.Context, flows []kestra.FlowRef, disabled bool) (*kestra.BulkResult, *kestra.Response, error) {
+	m.record("SetDisabledByIDs", ctx, flows, disabled)
+	if m.SetDisabledByIDsFunc != nil {
+		return m.SetDisabledByIDsFunc(ctx, flows, disabled)
+	}
+	var r0 *kestra.BulkResult
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// SetDisabledByQuery records the call and calls SetDisabledByQueryFunc.
+func (m *FlowAPI) SetDisabledByQuery(ctx context.Context, filter *kestra.FlowFilter, disabled bool) (*kestra.BulkResult, *kestra.Response, error) {
+	m.record("SetDisabledByQuery", ctx, filter, disabled)
+	if m.SetDisabledByQueryFunc != nil {
+		return m.SetDisabledByQueryFunc(ctx, filter, disabled)
+	}
+	var r0 *kestra.BulkResult
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// Validate records the call and calls ValidateFunc.
+func (m *FlowAPI) Validate(ctx context.Context, sources ...string) ([]kestra.FlowValidation, *kestra.Response, error) {
+	m.record("Validate", ctx, sources)
+	if m.ValidateFunc != nil {
+		return m.ValidateFunc(ctx, sources...)
+	}
+	var r0 []kestra.FlowValidation
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// ValidateTask records the call and calls ValidateTaskFunc.
+func (m *FlowAPI) ValidateTask(ctx context.Context, source string) (*kestra.FlowValidation, *kestra.Response, error) {
+	m.record("ValidateTask", ctx, source)
+	if m.ValidateTaskFunc != nil {
+		return m.ValidateTaskFunc(ctx, source)
+	}
+	var r0 *kestra.FlowValidation
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// ValidateTrigger records the call and calls ValidateTriggerFunc.
+func (m *FlowAPI) ValidateTrigger(ctx context.Context, source string) (*kestra.FlowValidation, *kestra.Response, error) {
+	m.record("ValidateTrigger", ctx, source)
+	if m.ValidateTriggerFunc != nil {
+		return m.ValidateTriggerFunc(ctx, source)
+	}
+	var r0 *kestra.FlowValidation
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// ExportByQuery records the call and calls ExportByQueryFunc.
+func (m *FlowAPI) ExportByQuery(ctx context.Context, filter *kestra.FlowFilter, w io.Writer) (*kestra.Response, error) {
+	m.record("ExportByQuery", ctx, filter, w)
+	if m.ExportByQueryFunc != nil {
+		return m.ExportByQueryFunc(ctx, filter, w)
+	}
+	var r0 *kestra.Response
+	var r1 error
+	return r0, r1
+}
+
+// ExportByIDs records the call and calls ExportByIDsFunc.
+func (m *FlowAPI) ExportByIDs(ctx context.Context, flows []kestra.FlowRef, w io.Writer) (*kestra.Response, error) {
+	m.record("ExportByIDs", ctx, flows, w)
+	if m.ExportByIDsFunc != nil {
+		return m.ExportByIDsFunc(ctx, flows, w)
+	}
+	var r0 *kestra.Response
+	var r1 error
+	return r0, r1
+}
+
+// Import records the call and calls ImportFunc.
+func (m *FlowAPI) Import(ctx context.Context, r io.Reader) (*kestra.Response, error) {
+	m.record("Import", ctx, r)
+	if m.ImportFunc != nil {
+		return m.ImportFunc(ctx, r)
+	}
+	var r0 *kestra.Response
+	var r1 error
+	return r0, r1
+}
+
+// SyncNamespace records the call and calls SyncNamespaceFunc.
+func (m *FlowAPI) SyncNamespace(ctx context.Context, namespace string, sources []string, opts *kestra.SyncOptions) (*kestra.SyncResult, *kestra.Response, error) {
+	m.record("SyncNamespace", ctx, namespace, sources, opts)
+	if m.SyncNamespaceFunc != nil {
+		return m.SyncNamespaceFunc(ctx, namespace, sources, opts)
+	}
+	var r0 *kestra.SyncResult
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// ExecutionAPI is a mock of kestra.ExecutionAPI recording its calls.
+// Each method calls the matching Func field when set, and returns zero values otherwise.
+type ExecutionAPI struct {
+	recorder
+
+	GetFunc       func(ctx context.Context, executionID string) (*kestra.Execution, *kestra.Response, error)
+	CreateFunc    func(ctx context.Context, namespace string, flowId string, input map[string]string) (*kestra.Execution, *kestra.Response, error)
+	SearchFunc    func(ctx context.Context, opts *kestra.ExecutionSearchOptions) (*kestra.PagedResults[kestra.Execution], *kestra.Response, error)
+	SearchAllFunc func(ctx context.Context, opts *kestra.ExecutionSearchOptions) iter.Seq2[kestra.Execution, error]
+}
+
+var _ kestra.ExecutionAPI = (*ExecutionAPI)(nil)
+
+// Get records the call and calls GetFunc.
+func (m *ExecutionAPI) Get(ctx context.Context, executionID string) (*kestra.Execution, *kestra.Response, error) {
+	m.record("Get", ctx, executionID)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, executionID)
+	}
+	var r0 *kestra.Execution
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// Create records the call and calls CreateFunc.
+func (m *ExecutionAPI) Create(ctx context.Context, namespace string, flowId string, input map[string]string) (*kestra.Execution, *kestra.Response, error) {
+	m.record("Create", ctx, namespace, flowId, input)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, namespace, flowId, input)
+	}
+	var r0 *kestra.Execution
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// Search records the call and calls SearchFunc.
+func (m *ExecutionAPI) Search(ctx context.Context, opts *kestra.ExecutionSearchOptions) (*kestra.PagedResults[kestra.Execution], *kestra.Response, error) {
+	m.record("Search", ctx, opts)
+	if m.SearchFunc != nil {
+		return m.SearchFunc(ctx, opts)
+	}
+	var r0 *kestra.PagedResults[kestra.Execution]
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
+
+// SearchAll records the call and calls SearchAllFunc.
+func (m *ExecutionAPI) SearchAll(ctx context.Context, opts *kestra.ExecutionSearchOptions) iter.Seq2[kestra.Execution, error] {
+	m.record("SearchAll", ctx, opts)
+	if m.SearchAllFunc != nil {
+		return m.SearchAllFunc(ctx, opts)
+	}
+	var r0 iter.Seq2[kestra.Execution, error] = func(func(kestra.Execution, error) bool) {}
+	return r0
+}
+
+// LogAPI is a mock of kestra.LogAPI recording its calls.
+// Each method calls the matching Func field when set, and returns zero values otherwise.
+type LogAPI struct {
+	recorder
+
+	GetFunc func(ctx context.Context, executionID string) (*[]kestra.Log, *kestra.Response, error)
+}
+
+var _ kestra.LogAPI = (*LogAPI)(nil)
+
+// Get records the call and calls GetFunc.
+func (m *LogAPI) Get(ctx context.Context, executionID string) (*[]kestra.Log, *kestra.Response, error) {
+	m.record("Get", ctx, executionID)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, executionID)
+	}
+	var r0 *[]kestra.Log
+	var r1 *kestra.Response
+	var r2 error
+	return r0, r1, r2
+}
@@ -0,0 +1,1031 @@
+// Package kestratest provides an in-memory fake Kestra server for testing code that depends on the
+// go-kestra client, without running Kestra.
+//
+// The fake server keeps flows, executions and logs in memory and serves them with the same JSON
+// models as package v1. Executions move through a configurable list of states, one state each time
+// they are fetched, and every received request is recorded for assertions:
+//
+//	server := kestratest.NewServer()
+//	defer server.Close()
+//
+//	client := server.Client()
+//	client.Flow.Create(ctx, `{"id":"hello_world","namespace":"tutorial"}`)
+//	server.AssertRequested(t, http.MethodPost, "/api/v1/flows")
+package kestratest
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	kestra "github.com/skeletonarmydev/go-kestra/kestra-oss/v1"
+)
+
+// Version is the Kestra version reported by the fake server configs endpoint.
+const Version = "0.17.0"
+
+// DefaultExecutionStates are the states new executions move through by default.
+var DefaultExecutionStates = []string{"CREATED", "RUNNING", "SUCCESS"}
+
+// Request is a request received by the fake server.
+type Request struct {
+	Method string
+	// Path of the request, including the tenant if any, e.g. "/api/v1/flows/tutorial/hello_world".
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Option configures a Server created by NewServer.
+type Option func(*Server)
+
+// WithExecutionStates sets the states new executions move through, for every flow.
+// The first state is the state of the execution when it is created.
+func WithExecutionStates(states ...string) Option {
+	return func(s *Server) {
+		s.states = slices.Clone(states)
+	}
+}
+
+// WithVersion sets the version reported by the configs endpoint.
+func WithVersion(version string) Option {
+	return func(s *Server) {
+		s.version = version
+	}
+}
+
+type flowKey struct {
+	namespace string
+	id        string
+}
+
+// execution is the state of an execution of the fake server.
+type execution struct {
+	kestra.Execution
+	// states are the states the execution still has to move through.
+	states []string
+}
+
+// Server is an in-memory fake Kestra server. It is safe for concurrent use.
+type Server struct {
+	// Server is the underlying test server; its URL is the base URL of the fake Kestra.
+	*httptest.Server
+
+	mu         sync.Mutex
+	version    string
+	states     []string
+	flowStates map[flowKey][]string
+	flows      map[flowKey][]kestra.Flow // revisions, oldest first
+	executions map[string]*execution
+	logs       map[string][]kestra.Log
+	requests   []Request
+	now        func() time.Time
+}
+
+// NewServer starts and returns a new fake Kestra server. The caller should call Close when finished.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		version:    Version,
+		states:     slices.Clone(DefaultExecutionStates),
+		flowStates: make(map[flowKey][]string),
+		flows:      make(map[flowKey][]kestra.Flow),
+		executions: make(map[string]*execution),
+		logs:       make(map[string][]kestra.Log),
+		now:        func() time.Time { return time.Now().UTC() },
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns a client configured to talk to the fake server.
+func (s *Server) Client(opts ...kestra.ClientOption) *kestra.Client {
+	client, err := kestra.NewClient(s.URL, nil, opts...)
+	if err != nil {
+		panic("kestratest: " + err.Error())
+	}
+	return client
+}
+
+// SetExecutionStates sets the states the new executions of a flow move through,
+// overriding WithExecutionStates for this flow.
+func (s *Server) SetExecutionStates(namespace, flowID string, states ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flowStates[flowKey{namespace, flowID}] = slices.Clone(states)
+}
+
+// AddFlow stores a flow from its YAML (or JSON) source, as a new revision if the flow already exists.
+func (s *Server) AddFlow(source string) (*kestra.Flow, error) {
+	flow, err := kestra.ParseFlowYAML(source)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := s.putFlow(*flow)
+	return &stored, nil
+}
+
+// Flow returns the latest revision of a flow.
+func (s *Server) Flow(namespace, flowID string) (*kestra.Flow, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	revisions := s.flows[flowKey{namespace, flowID}]
+	if len(revisions) == 0 {
+		return nil, false
+	}
+	flow := revisions[len(revisions)-1]
+	return &flow, true
+}
+
+// Flows returns the latest revision of every flow, sorted by namespace and ID.
+func (s *Server) Flows() []kestra.Flow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latestFlows()
+}
+
+// Execution returns an execution, without moving it to its next state.
+func (s *Server) Execution(id string) (*kestra.Execution, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.executions[id]
+	if !ok {
+		return nil, false
+	}
+	execution := e.Execution
+	return &execution, true
+}
+
+// Logs returns the logs of an execution.
+func (s *Server) Logs(executionID string) []kestra.Log {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.logs[executionID])
+}
+
+// Requests returns every request received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// RequestsTo returns the requests received for the given method and path.
+func (s *Server) RequestsTo(method, path string) []Request {
+	var requests []Request
+	for _, r := range s.Requests() {
+		if r.Method == method && r.Path == path {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+// AssertRequested fails the test if no request was received for the given method and path.
+func (s *Server) AssertRequested(t testing.TB, method, path string) {
+	t.Helper()
+	if len(s.RequestsTo(method, path)) == 0 {
+		t.Errorf("kestratest: no %s %s request received, got %s", method, path, s.describeRequests())
+	}
+}
+
+// AssertNotRequested fails the test if a request was received for the given method and path.
+func (s *Server) AssertNotRequested(t testing.TB, method, path string) {
+	t.Helper()
+	if n := len(s.RequestsTo(method, path)); n > 0 {
+		t.Errorf("kestratest: %d unexpected %s %s requests received", n, method, path)
+	}
+}
+
+func (s *Server) describeRequests() string {
+	var lines []string
+	for _, r := range s.Requests() {
+		lines = append(lines, r.Method+" "+r.Path)
+	}
+	if len(lines) == 0 {
+		return "none"
+	}
+	return strings.Join(lines, ", ")
+}
+
+// serveHTTP records the request and routes it. Both the legacy (/api/v1/flows) and the
+// tenant-aware (/api/v1/{tenant}/flows) routes are served.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+
+	path, ok := strings.CutPrefix(r.URL.Path, "/api/v1/")
+	if !ok {
+		writeError(w, http.StatusNotFound, "Page Not Found")
+		return
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	switch segments[0] {
+	case "configs", "flows", "executions", "logs":
+	default:
+		segments = segments[1:] // tenant
+	}
+	if len(segments) == 0 {
+		writeError(w, http.StatusNotFound, "Page Not Found")
+		return
+	}
+
+	switch {
+	case segments[0] == "configs" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"version": s.version, "edition": "OSS"})
+	case segments[0] == "flows":
+		s.serveFlows(w, r, segments[1:], body)
+	case segments[0] == "executions":
+		s.serveExecutions(w, r, segments[1:], body)
+	case segments[0] == "logs" && len(segments) == 2 && r.Method == http.MethodGet:
+		s.serveLogs(w, segments[1])
+	default:
+		writeError(w, http.StatusNotFound, "Page Not Found")
+	}
+}
+
+func (s *Server) serveFlows(w http.ResponseWriter, r *http.Request, segments []string, body []byte) {
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		flow, err := kestra.ParseFlowYAML(string(body))
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid entity: "+err.Error())
+			return
+		}
+		if msg := validateFlow(flow); msg != "" {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid entity: "+msg)
+			return
+		}
+		if _, exists := s.flows[flowKey{flow.Namespace, flow.ID}]; exists {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid entity: flow id already exists")
+			return
+		}
+		writeJSON(w, http.StatusOK, withoutSource(s.putFlow(*flow)))
+
+	case len(segments) == 1 && segments[0] == "search" && r.Method == http.MethodGet:
+		s.searchFlows(w, r.URL.Query())
+
+	case len(segments) == 1 && segments[0] == "validate" && r.Method == http.MethodPost:
+		s.validateFlows(w, body)
+
+	case len(segments) == 2 && segments[0] == "validate" && segments[1] == "task" && r.Method == http.MethodPost:
+		validation := kestra.FlowValidation{}
+		if msg := validateFragment(body); msg != "" {
+			validation.Constraints = []string{msg}
+		}
+		writeJSON(w, http.StatusOK, validation)
+
+	case len(segments) == 2 && segments[0] == "export" && segments[1] == "by-query" && r.Method == http.MethodGet:
+		s.exportFlows(w, s.matchFlows(r.URL.Query()))
+
+	case len(segments) == 2 && segments[0] == "export" && segments[1] == "by-ids" && r.Method == http.MethodPost:
+		var refs []kestra.FlowRef
+		if err := json.Unmarshal(body, &refs); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+			return
+		}
+		var flows []kestra.Flow
+		for _, ref := range refs {
+			if revisions := s.flows[flowKey{ref.Namespace, ref.ID}]; len(revisions) > 0 {
+				flows = append(flows, revisions[len(revisions)-1])
+			}
+		}
+		s.exportFlows(w, flows)
+
+	case len(segments) == 1 && segments[0] == "import" && r.Method == http.MethodPost:
+		s.importFlows(w, r)
+
+	case len(segments) == 2 && (segments[0] == "enable" || segments[0] == "disable") && segments[1] == "by-ids" && r.Method == http.MethodPost:
+		var refs []kestra.FlowRef
+		if err := json.Unmarshal(body, &refs); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+			return
+		}
+		count := 0
+		for _, ref := range refs {
+			if s.setDisabled(flowKey{ref.Namespace, ref.ID}, segments[0] == "disable") {
+				count++
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"count": count})
+
+	case len(segments) == 2 && (segments[0] == "enable" || segments[0] == "disable") && segments[1] == "by-query" && r.Method == http.MethodPost:
+		count := 0
+		for _, flow := range s.matchFlows(r.URL.Query()) {
+			if s.setDisabled(flowKey{flow.Namespace, flow.ID}, segments[0] == "disable") {
+				count++
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"count": count})
+
+	case len(segments) == 2 && segments[0] == "delete" && segments[1] == "by-ids" && r.Method == http.MethodDelete:
+		var refs []kestra.FlowRef
+		if err := json.Unmarshal(body, &refs); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+			return
+		}
+		count := 0
+		for _, ref := range refs {
+			if s.deleteFlow(flowKey{ref.Namespace, ref.ID}) {
+				count++
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"count": count})
+
+	case len(segments) == 2 && segments[0] == "delete" && segments[1] == "by-query" && r.Method == http.MethodDelete:
+		count := 0
+		for _, flow := range s.matchFlows(r.URL.Query()) {
+			if s.deleteFlow(flowKey{flow.Namespace, flow.ID}) {
+				count++
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"count": count})
+
+	case len(segments) == 1 && r.Method == http.MethodPost:
+		s.updateNamespace(w, segments[0], r.URL.Query().Get("delete") != "false", body)
+
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		flows := []kestra.Flow{}
+		for _, flow := range s.latestFlows() {
+			if flow.Namespace == segments[0] {
+				flows = append(flows, withoutSource(flow))
+			}
+		}
+		writeJSON(w, http.StatusOK, flows)
+
+	case len(segments) == 3 && segments[2] == "revisions" && r.Method == http.MethodGet:
+		revisions := s.flows[flowKey{segments[0], segments[1]}]
+		if len(revisions) == 0 {
+			writeError(w, http.StatusNotFound, "Flow not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, revisions)
+
+	case len(segments) == 3 && segments[2] == "dependencies" && r.Method == http.MethodGet:
+		if len(s.flows[flowKey{segments[0], segments[1]}]) == 0 {
+			writeError(w, http.StatusNotFound, "Flow not found")
+			return
+		}
+		graph := kestra.AnalyzeDependencies(s.latestFlows())
+		ref := kestra.FlowRef{Namespace: segments[0], ID: segments[1]}
+		writeJSON(w, http.StatusOK, graph.Subgraph(ref, r.URL.Query().Get("expandAll") == "true"))
+
+	case len(segments) == 3 && segments[2] == "graph" && r.Method == http.MethodGet:
+		revisions := s.flows[flowKey{segments[0], segments[1]}]
+		if len(revisions) == 0 {
+			writeError(w, http.StatusNotFound, "Flow not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, flowGraph(revisions[len(revisions)-1]))
+
+	case len(segments) == 2 && r.Method == http.MethodGet:
+		revisions := s.flows[flowKey{segments[0], segments[1]}]
+		if len(revisions) == 0 {
+			writeError(w, http.StatusNotFound, "Flow not found")
+			return
+		}
+		flow := revisions[len(revisions)-1]
+		if revision := r.URL.Query().Get("revision"); revision != "" {
+			n, err := strconv.Atoi(revision)
+			if err != nil || n < 1 || n > len(revisions) {
+				writeError(w, http.StatusNotFound, "Flow revision not found")
+				return
+			}
+			flow = revisions[n-1]
+		}
+		if r.URL.Query().Get("source") != "true" {
+			flow = withoutSource(flow)
+		}
+		writeJSON(w, http.StatusOK, flow)
+
+	case len(segments) == 2 && r.Method == http.MethodPut:
+		key := flowKey{segments[0], segments[1]}
+		if len(s.flows[key]) == 0 {
+			writeError(w, http.StatusNotFound, "Flow not found")
+			return
+		}
+		flow, err := kestra.ParseFlowYAML(string(body))
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid entity: "+err.Error())
+			return
+		}
+		if flow.Namespace != key.namespace || flow.ID != key.id {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid entity: flow id and namespace cannot be changed")
+			return
+		}
+		if msg := validateFlow(flow); msg != "" {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid entity: "+msg)
+			return
+		}
+		// as Kestra, keep the revision of an unchanged flow
+		if latest := s.flows[key][len(s.flows[key])-1]; latest.Source == flow.Source {
+			writeJSON(w, http.StatusOK, withoutSource(latest))
+			return
+		}
+		writeJSON(w, http.StatusOK, withoutSource(s.putFlow(*flow)))
+
+	case len(segments) == 2 && r.Method == http.MethodDelete:
+		if !s.deleteFlow(flowKey{segments[0], segments[1]}) {
+			writeError(w, http.StatusNotFound, "Flow not found")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, http.StatusNotFound, "Page Not Found")
+	}
+}
+
+func (s *Server) searchFlows(w http.ResponseWriter, query url.Values) {
+	matches := s.matchFlows(query)
+	// matches are sorted by namespace and ID; only the first sort field is supported, on id or namespace
+	if field, direction, _ := strings.Cut(query.Get("sort"), ":"); field == "id" || field == "namespace" {
+		sort.SliceStable(matches, func(i, j int) bool {
+			a, b := matches[i].ID, matches[j].ID
+			if field == "namespace" {
+				a, b = matches[i].Namespace, matches[j].Namespace
+			}
+			if direction == "desc" {
+				return a > b
+			}
+			return a < b
+		})
+	}
+	writeJSON(w, http.StatusOK, kestra.PagedResults[kestra.Flow]{Results: page(matches, query), Total: len(matches)})
+}
+
+func (s *Server) serveExecutions(w http.ResponseWriter, r *http.Request, segments []string, body []byte) {
+	switch {
+	case len(segments) == 1 && segments[0] == "search" && r.Method == http.MethodGet:
+		s.searchExecutions(w, r.URL.Query())
+
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		e, ok := s.executions[segments[0]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Execution not found")
+			return
+		}
+		s.advance(e)
+		writeJSON(w, http.StatusOK, e.Execution)
+
+	case len(segments) == 2 && r.Method == http.MethodPost:
+		revisions := s.flows[flowKey{segments[0], segments[1]}]
+		if len(revisions) == 0 {
+			writeError(w, http.StatusNotFound, "Flow not found")
+			return
+		}
+		e := s.createExecution(revisions[len(revisions)-1], parseInputs(r, body))
+		writeJSON(w, http.StatusOK, e.Execution)
+
+	default:
+		writeError(w, http.StatusNotFound, "Page Not Found")
+	}
+}
+
+func (s *Server) searchExecutions(w http.ResponseWriter, query url.Values) {
+	states := query["state"]
+
+	matches := []kestra.Execution{}
+	for _, e := range s.executions {
+		if ns := query.Get("namespace"); ns != "" && e.Namespace != ns && !strings.HasPrefix(e.Namespace, ns+".") {
+			continue
+		}
+		if flowID := query.Get("flowId"); flowID != "" && e.FlowID != flowID {
+			continue
+		}
+		if len(states) > 0 && !slices.Contains(states, e.State.Current) {
+			continue
+		}
+		matches = append(matches, e.Execution)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].State.StartDate.Before(matches[j].State.StartDate) ||
+			matches[i].State.StartDate.Equal(matches[j].State.StartDate) && matches[i].ID < matches[j].ID
+	})
+
+	writeJSON(w, http.StatusOK, kestra.PagedResults[kestra.Execution]{Results: page(matches, query), Total: len(matches)})
+}
+
+func (s *Server) serveLogs(w http.ResponseWriter, executionID string) {
+	if _, ok := s.executions[executionID]; !ok {
+		writeError(w, http.StatusNotFound, "Execution not found")
+		return
+	}
+	logs := s.logs[executionID]
+	if logs == nil {
+		logs = []kestra.Log{}
+	}
+	writeJSON(w, http.StatusOK, logs)
+}
+
+// matchFlows returns the latest revision of the flows matching the q, namespace and labels
+// search parameters, without their source. s.mu must be held.
+func (s *Server) matchFlows(query url.Values) []kestra.Flow {
+	q := strings.ToLower(query.Get("q"))
+	namespace := query.Get("namespace")
+
+	matches := []kestra.Flow{}
+	for _, flow := range s.latestFlows() {
+		if q != "" && !strings.Contains(strings.ToLower(flow.ID), q) && !strings.Contains(strings.ToLower(flow.Description), q) {
+			continue
+		}
+		if namespace != "" && flow.Namespace != namespace && !strings.HasPrefix(flow.Namespace, namespace+".") {
+			continue
+		}
+		if !hasLabels(flow, query["labels"]) {
+			continue
+		}
+		matches = append(matches, withoutSource(flow))
+	}
+	return matches
+}
+
+// hasLabels reports whether flow has every label, formatted as "key:value".
+func hasLabels(flow kestra.Flow, labels []string) bool {
+	for _, label := range labels {
+		key, value, _ := strings.Cut(label, ":")
+		if v, ok := flow.Labels.Get(key); !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// deleteFlow deletes every revision of a flow and reports whether it existed. s.mu must be held.
+func (s *Server) deleteFlow(key flowKey) bool {
+	if len(s.flows[key]) == 0 {
+		return false
+	}
+	delete(s.flows, key)
+	return true
+}
+
+// setDisabled stores a new revision of a flow with the given disabled state, and reports whether
+// the flow exists. s.mu must be held.
+func (s *Server) setDisabled(key flowKey, disabled bool) bool {
+	revisions := s.flows[key]
+	if len(revisions) == 0 {
+		return false
+	}
+	flow := revisions[len(revisions)-1]
+	flow.Disabled = disabled
+	s.putFlow(flow)
+	return true
+}
+
+// putFlow stores flow as the next revision of its flow and returns it. s.mu must be held.
+func (s *Server) putFlow(flow kestra.Flow) kestra.Flow {
+	key := flowKey{flow.Namespace, flow.ID}
+	flow.Revision = json.Number(strconv.Itoa(len(s.flows[key]) + 1))
+	s.flows[key] = append(s.flows[key], flow)
+	return flow
+}
+
+// latestFlows returns the latest revision of every flow. s.mu must be held.
+func (s *Server) latestFlows() []kestra.Flow {
+	flows := make([]kestra.Flow, 0, len(s.flows))
+	for _, revisions := range s.flows {
+		flows = append(flows, revisions[len(revisions)-1])
+	}
+	sort.Slice(flows, func(i, j int) bool {
+		if flows[i].Namespace != flows[j].Namespace {
+			return flows[i].Namespace < flows[j].Namespace
+		}
+		return flows[i].ID < flows[j].ID
+	})
+	return flows
+}
+
+// createExecution creates an execution of flow in its first state. s.mu must be held.
+func (s *Server) createExecution(flow kestra.Flow, inputs map[string]string) *execution {
+	states := s.flowStates[flowKey{flow.Namespace, flow.ID}]
+	if states == nil {
+		states = s.states
+	}
+	if len(states) == 0 {
+		states = DefaultExecutionStates
+	}
+
+	e := &execution{
+		Execution: kestra.Execution{
+			ID:           newID(),
+			Namespace:    flow.Namespace,
+			FlowID:       flow.ID,
+			FlowRevision: flow.Revision,
+		},
+		states: slices.Clone(states),
+	}
+	for _, task := range flow.Tasks {
+		e.TaskRunList = append(e.TaskRunList, kestra.ExecutionTaskRun{
+			ID:          newID(),
+			TaskId:      task.ID,
+			Description: task.Description,
+		})
+	}
+
+	s.executions[e.ID] = e
+	s.advance(e)
+
+	names := make([]string, 0, len(inputs))
+	for name := range inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s.log(e, "", "DEBUG", fmt.Sprintf("Input %s: %s", name, inputs[name]))
+	}
+
+	return e
+}
+
+// advance moves an execution to its next state, if any. s.mu must be held.
+func (s *Server) advance(e *execution) {
+	if len(e.states) == 0 {
+		return
+	}
+
+	now := s.now()
+	state := e.states[0]
+	e.states = e.states[1:]
+
+	if e.State.StartDate.IsZero() {
+		e.State.StartDate = now
+	}
+	e.State.Current = state
+	e.State.History = append(e.State.History, kestra.ExecutionHistory{State: state, Date: now})
+	e.State.Duration = durationString(now.Sub(e.State.StartDate))
+	if len(e.states) == 0 {
+		e.State.EndDate = now
+	}
+
+	for i := range e.TaskRunList {
+		run := &e.TaskRunList[i]
+		if state == "CREATED" {
+			continue
+		}
+		if run.State.StartDate.IsZero() {
+			run.State.StartDate = now
+		}
+		run.State.Current = state
+		run.State.Duration = durationString(now.Sub(run.State.StartDate))
+		if len(e.states) == 0 {
+			run.State.EndDate = now
+			s.log(e, run.TaskId, "INFO", fmt.Sprintf("Task %s ended in state %s", run.TaskId, state))
+		}
+	}
+
+	s.log(e, "", "INFO", fmt.Sprintf("Execution %s is %s", e.ID, state))
+}
+
+// log appends a log line to an execution. s.mu must be held.
+func (s *Server) log(e *execution, taskID string, level string, message string) {
+	entry := kestra.Log{
+		TaskId:      taskID,
+		Namespace:   e.Namespace,
+		FlowID:      e.FlowID,
+		ExecutionId: e.ID,
+		Timestamp:   s.now(),
+		Level:       level,
+		Message:     message,
+	}
+	for _, run := range e.TaskRunList {
+		if run.TaskId == taskID {
+			entry.TaskRunId = run.ID
+		}
+	}
+	s.logs[e.ID] = append(s.logs[e.ID], entry)
+}
+
+// exportFlows writes a ZIP archive of the sources of flows, named "{namespace}.{id}.yml" as Kestra does.
+// s.mu must be held.
+func (s *Server) exportFlows(w http.ResponseWriter, flows []kestra.Flow) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, flow := range flows {
+		revisions := s.flows[flowKey{flow.Namespace, flow.ID}]
+		f, err := archive.Create(flow.Namespace + "." + flow.ID + ".yml")
+		if err == nil {
+			_, err = io.WriteString(f, revisions[len(revisions)-1].Source)
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if err := archive.Close(); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", "attachment; filename=flows.zip")
+	w.Write(buf.Bytes())
+}
+
+// importFlows stores the flows of the uploaded "fileUpload" file, a ZIP archive or a single YAML source.
+// s.mu must be held.
+func (s *Server) importFlows(w http.ResponseWriter, r *http.Request) {
+	file, header, err := r.FormFile("fileUpload")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Missing fileUpload: "+err.Error())
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var sources []string
+	if strings.HasSuffix(header.Filename, ".zip") {
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid archive: "+err.Error())
+			return
+		}
+		for _, f := range archive.File {
+			rc, err := f.Open()
+			if err != nil {
+				writeError(w, http.StatusUnprocessableEntity, "Invalid archive: "+err.Error())
+				return
+			}
+			source, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				writeError(w, http.StatusUnprocessableEntity, "Invalid archive: "+err.Error())
+				return
+			}
+			sources = append(sources, string(source))
+		}
+	} else {
+		sources = append(sources, string(data))
+	}
+
+	var flows []*kestra.Flow
+	for _, source := range sources {
+		flow, err := kestra.ParseFlowYAML(source)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid entity: "+err.Error())
+			return
+		}
+		if msg := validateFlow(flow); msg != "" {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid entity: "+msg)
+			return
+		}
+		flows = append(flows, flow)
+	}
+	for _, flow := range flows {
+		s.putFlow(*flow)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// updateNamespace replaces the flows of a namespace with the flows of a multi-document YAML body,
+// deleting the other flows of the namespace if del is set. s.mu must be held.
+func (s *Server) updateNamespace(w http.ResponseWriter, namespace string, del bool, body []byte) {
+	var flows []*kestra.Flow
+	ids := make(map[string]bool)
+	for _, source := range splitDocuments(string(body)) {
+		flow, err := kestra.ParseFlowYAML(strings.TrimSpace(source))
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid entity: "+err.Error())
+			return
+		}
+		if msg := validateFlow(flow); msg != "" {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid entity: "+msg)
+			return
+		}
+		if flow.Namespace != namespace {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid entity: flow namespace is invalid")
+			return
+		}
+		flows = append(flows, flow)
+		ids[flow.ID] = true
+	}
+
+	updated := []kestra.Flow{}
+	for _, flow := range flows {
+		revisions := s.flows[flowKey{flow.Namespace, flow.ID}]
+		if len(revisions) > 0 && revisions[len(revisions)-1].Source == flow.Source {
+			updated = append(updated, withoutSource(revisions[len(revisions)-1]))
+			continue
+		}
+		updated = append(updated, withoutSource(s.putFlow(*flow)))
+	}
+	if del {
+		for key := range s.flows {
+			if key.namespace == namespace && !ids[key.id] {
+				s.deleteFlow(key)
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// validateFlows validates the flows of a multi-document YAML body. s.mu must be held.
+func (s *Server) validateFlows(w http.ResponseWriter, body []byte) {
+	validations := []kestra.FlowValidation{}
+	for i, source := range splitDocuments(string(body)) {
+		validation := kestra.FlowValidation{Index: i}
+		flow, err := kestra.ParseFlowYAML(source)
+		if err != nil {
+			validation.Constraints = []string{err.Error()}
+		} else {
+			validation.Flow, validation.Namespace = flow.ID, flow.Namespace
+			if msg := validateFlow(flow); msg != "" {
+				validation.Constraints = []string{msg}
+			}
+			if revisions := s.flows[flowKey{flow.Namespace, flow.ID}]; flow.Revision != "" && len(revisions) > 0 {
+				latest, _ := revisions[len(revisions)-1].Revision.Int64()
+				revision, _ := flow.Revision.Int64()
+				validation.Outdated = revision < latest
+			}
+		}
+		validations = append(validations, validation)
+	}
+	writeJSON(w, http.StatusOK, validations)
+}
+
+// splitDocuments splits a multi-document YAML source on its "---" separators.
+func splitDocuments(source string) []string {
+	var documents []string
+	var current []string
+	for _, line := range strings.Split(source, "\n") {
+		if strings.TrimRight(line, " ") == "---" {
+			documents = append(documents, strings.Join(current, "\n"))
+			current = nil
+			continue
+		}
+		current = append(current, line)
+	}
+	documents = append(documents, strings.Join(current, "\n"))
+
+	nonEmpty := documents[:0]
+	for _, document := range documents {
+		if strings.TrimSpace(document) != "" {
+			nonEmpty = append(nonEmpty, document)
+		}
+	}
+	return nonEmpty
+}
+
+// validateFragment returns the first constraint violation of a task or trigger source, or an empty string.
+func validateFragment(body []byte) string {
+	var fragment struct {
+		ID   string `yaml:"id"`
+		Type string `yaml:"type"`
+	}
+	if err := yaml.Unmarshal(body, &fragment); err != nil {
+		return err.Error()
+	}
+	if fragment.ID == "" || fragment.Type == "" {
+		return "id and type are required"
+	}
+	return ""
+}
+
+// validateFlow returns the first constraint violation of flow, or an empty string.
+func validateFlow(flow *kestra.Flow) string {
+	switch {
+	case flow.ID == "":
+		return "flow.id: must not be null"
+	case flow.Namespace == "":
+		return "flow.namespace: must not be null"
+	case len(flow.Tasks) == 0:
+		return "flow.tasks: must not be empty"
+	}
+	for _, task := range flow.Tasks {
+		if task.ID == "" || task.Type == "" {
+			return "flow.tasks: id and type are required"
+		}
+	}
+	return ""
+}
+
+// flowGraph returns the graph of flow as a sequence of its top-level tasks, between the root and
+// end nodes Kestra uses. Flowable tasks are not expanded into clusters.
+func flowGraph(flow kestra.Flow) kestra.FlowGraph {
+	const hierarchies = "io.kestra.core.models.hierarchies."
+	graph := kestra.FlowGraph{
+		Nodes:    []kestra.GraphNode{{UID: "root.root", Type: hierarchies + "GraphClusterRoot"}},
+		Edges:    []kestra.GraphEdge{},
+		Clusters: []kestra.GraphCluster{},
+	}
+	previous := "root.root"
+	for _, task := range flow.Tasks {
+		uid := "root." + task.ID
+		graph.Nodes = append(graph.Nodes, kestra.GraphNode{UID: uid, Type: hierarchies + "GraphTask", Task: &task, RelationType: kestra.GraphSequential})
+		graph.Edges = append(graph.Edges, kestra.GraphEdge{Source: previous, Target: uid, Relation: kestra.GraphRelation{RelationType: kestra.GraphSequential}})
+		previous = uid
+	}
+	graph.Nodes = append(graph.Nodes, kestra.GraphNode{UID: "root.end", Type: hierarchies + "GraphClusterEnd"})
+	graph.Edges = append(graph.Edges, kestra.GraphEdge{Source: previous, Target: "root.end", Relation: kestra.GraphRelation{}})
+	return graph
+}
+
+// parseInputs returns the inputs of an execution request, sent either as a multipart form
+// or as an URL encoded body.
+func parseInputs(r *http.Request, body []byte) map[string]string {
+	inputs := make(map[string]string)
+	if err := r.ParseMultipartForm(1 << 20); err == nil {
+		for name, values := range r.MultipartForm.Value {
+			inputs[name] = values[0]
+		}
+		return inputs
+	}
+
+	values, _ := url.ParseQuery(string(body))
+	for name := range values {
+		inputs[name] = values.Get(name)
+	}
+	return inputs
+}
+
+// page returns the page of items selected by the page and size query parameters.
+func page[T any](items []T, query url.Values) []T {
+	size, err := strconv.Atoi(query.Get("size"))
+	if err != nil || size <= 0 {
+		size = 10
+	}
+	p, err := strconv.Atoi(query.Get("page"))
+	if err != nil || p <= 0 {
+		p = 1
+	}
+
+	from, to := min((p-1)*size, len(items)), min(p*size, len(items))
+	return items[from:to]
+}
+
+func withoutSource(flow kestra.Flow) kestra.Flow {
+	flow.Source = ""
+	return flow
+}
+
+// newID returns a random ID in the style of Kestra IDs.
+func newID() string {
+	const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	id := make([]byte, 22)
+	for i := range id {
+		id[i] = alphabet[rand.IntN(len(alphabet))]
+	}
+	return string(id)
+}
+
+// durationString formats d as an ISO-8601 duration, as Kestra does.
+func durationString(d time.Duration) string {
+	return "PT" + strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S"
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error body in the format used by Kestra.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"message": message,
+		"_embedded": map[string]interface{}{
+			"errors": []map[string]string{{"message": message}},
+		},
+	})
+}
@@ -0,0 +1,501 @@
+package kestratest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	kestra "github.com/skeletonarmydev/go-kestra/kestra-oss/v1"
+)
+
+const helloWorld = `id: hello_world
+namespace: tutorial
+description: Hello World
+tasks:
+  - id: log
+    type: io.kestra.plugin.core.log.Log
+    message: Hello World
+`
+
+func TestServer_Flows(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client := server.Client()
+	ctx := context.Background()
+
+	created, _, err := client.Flow.Create(ctx, `{"id":"hello_world","namespace":"tutorial","tasks":[{"id":"log","type":"io.kestra.plugin.core.log.Log"}]}`)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if created.Revision != "1" {
+		t.Errorf("Create() revision = %v, want 1", created.Revision)
+	}
+
+	_, _, err = client.Flow.Create(ctx, `{"id":"hello_world","namespace":"tutorial","tasks":[{"id":"log","type":"io.kestra.plugin.core.log.Log"}]}`)
+	if !errors.Is(err, kestra.ErrUnprocessable) {
+		t.Errorf("Create() of an existing flow error = %v, want %v", err, kestra.ErrUnprocessable)
+	}
+
+	updated, _, err := client.Flow.Update(ctx, "tutorial", "hello_world", helloWorld)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.Revision != "2" || updated.Description != "Hello World" {
+		t.Errorf("Update() got = %v", updated)
+	}
+
+	source, _, err := client.Flow.GetSource(ctx, "tutorial", "hello_world")
+	if err != nil || source != helloWorld {
+		t.Errorf("GetSource() got = %q, error = %v", source, err)
+	}
+
+	if _, err := server.AddFlow("id: other\nnamespace: company.team\ntasks:\n  - id: log\n    type: io.kestra.plugin.core.log.Log\n"); err != nil {
+		t.Fatalf("AddFlow() error = %v", err)
+	}
+
+	all, _, err := client.Flow.GetAll(ctx, "tutorial")
+	if err != nil || len(*all) != 1 {
+		t.Errorf("GetAll() got = %v, error = %v", all, err)
+	}
+
+	result, resp, err := client.Flow.Search(ctx, &kestra.FlowSearchOptions{FlowFilter: kestra.FlowFilter{Query: "hello"}})
+	if err != nil || result.Total != 1 || result.Results[0].ID != "hello_world" {
+		t.Errorf("Search() got = %v, error = %v", result, err)
+	}
+	if resp.Total != 1 {
+		t.Errorf("Search() Response.Total = %v, want 1", resp.Total)
+	}
+
+	if _, err := server.AddFlow("id: another\nnamespace: company.team.data\nlabels:\n  team: payments\ntasks:\n  - id: log\n    type: io.kestra.plugin.core.log.Log\n"); err != nil {
+		t.Fatalf("AddFlow() error = %v", err)
+	}
+	result, _, err = client.Flow.Search(ctx, &kestra.FlowSearchOptions{
+		FlowFilter:  kestra.FlowFilter{Namespace: "company.team"},
+		ListOptions: kestra.ListOptions{Sort: []string{kestra.SortBy("id", kestra.SortDesc)}},
+	})
+	if err != nil || result.Total != 2 || result.Results[0].ID != "other" || result.Results[1].ID != "another" {
+		t.Errorf("Search() of a namespace got = %v, error = %v", result, err)
+	}
+	result, _, err = client.Flow.Search(ctx, &kestra.FlowSearchOptions{FlowFilter: kestra.FlowFilter{Labels: map[string]string{"team": "payments"}}})
+	if err != nil || result.Total != 1 || result.Results[0].ID != "another" {
+		t.Errorf("Search() of a label got = %v, error = %v", result, err)
+	}
+
+	missing, resp, err := client.Flow.Get(ctx, "tutorial", "missing")
+	if err != nil || missing != nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Get() of a missing flow got = %v, error = %v", missing, err)
+	}
+
+	server.AssertRequested(t, http.MethodPut, "/api/v1/flows/tutorial/hello_world")
+	server.AssertNotRequested(t, http.MethodDelete, "/api/v1/flows/tutorial/hello_world")
+	if got := server.RequestsTo(http.MethodPut, "/api/v1/flows/tutorial/hello_world"); string(got[0].Body) != helloWorld {
+		t.Errorf("recorded body = %q, want %q", got[0].Body, helloWorld)
+	}
+}
+
+func TestServer_ValidateFlows(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client := server.Client()
+	ctx := context.Background()
+
+	validations, _, err := client.Flow.Validate(ctx, helloWorld, "id: empty\nnamespace: tutorial\n")
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if len(validations) != 2 || !validations[0].Valid() || validations[1].Valid() || validations[1].Flow != "empty" {
+		t.Errorf("Validate() got = %+v", validations)
+	}
+	if _, ok := server.Flow("tutorial", "hello_world"); ok {
+		t.Errorf("Validate() created the flow")
+	}
+
+	task, _, err := client.Flow.ValidateTask(ctx, "id: log\n")
+	if err != nil || task.Valid() {
+		t.Errorf("ValidateTask() got = %+v, error = %v", task, err)
+	}
+}
+
+func TestServer_SyncNamespace(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client := server.Client()
+	ctx := context.Background()
+
+	if _, err := server.AddFlow("id: old\nnamespace: tutorial\ntasks:\n  - id: log\n    type: io.kestra.plugin.core.log.Log"); err != nil {
+		t.Fatalf("AddFlow() error = %v", err)
+	}
+
+	result, _, err := client.Flow.SyncNamespace(ctx, "tutorial", []string{helloWorld}, &kestra.SyncOptions{Delete: true, DryRun: true})
+	if err != nil || len(result.Changes) != 2 || result.Changes[0].Action != kestra.SyncCreate || result.Changes[1].Action != kestra.SyncDelete {
+		t.Errorf("dry run SyncNamespace() got = %+v, error = %v", result, err)
+	}
+
+	result, _, err = client.Flow.SyncNamespace(ctx, "tutorial", []string{helloWorld}, &kestra.SyncOptions{Delete: true})
+	if err != nil {
+		t.Fatalf("SyncNamespace() error = %v", err)
+	}
+	if len(result.Changes) != 2 || result.Changes[0].Action != kestra.SyncCreate || result.Changes[1].Action != kestra.SyncDelete {
+		t.Errorf("SyncNamespace() changes = %v", result.Changes)
+	}
+
+	result, _, err = client.Flow.SyncNamespace(ctx, "tutorial", []string{helloWorld}, nil)
+	if err != nil || len(result.Changes) != 1 || result.Changes[0].Action != kestra.SyncUnchanged {
+		t.Errorf("second SyncNamespace() got = %+v, error = %v", result, err)
+	}
+	if flows := server.Flows(); len(flows) != 1 || flows[0].ID != "hello_world" || flows[0].Revision != "1" {
+		t.Errorf("Flows() got = %v", flows)
+	}
+}
+
+func TestServer_Revisions(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client := server.Client()
+	ctx := context.Background()
+
+	first := "id: hello_world\nnamespace: tutorial\ntasks:\n  - id: log\n    type: io.kestra.plugin.core.log.Log\n"
+	if _, err := server.AddFlow(first); err != nil {
+		t.Fatalf("AddFlow() error = %v", err)
+	}
+	if _, err := server.AddFlow(helloWorld); err != nil {
+		t.Fatalf("AddFlow() error = %v", err)
+	}
+
+	revisions, _, err := client.Flow.Revisions(ctx, "tutorial", "hello_world")
+	if err != nil || len(revisions) != 2 || revisions[1].Source != helloWorld {
+		t.Fatalf("Revisions() got = %v, error = %v", revisions, err)
+	}
+
+	revision, _, err := client.Flow.GetRevision(ctx, "tutorial", "hello_world", 1)
+	if err != nil || revision.Revision != "1" || revision.Source != first {
+		t.Errorf("GetRevision() got = %v, error = %v", revision, err)
+	}
+	if diff := kestra.DiffRevisions(revision, &revisions[1]); !strings.Contains(diff, "+description: Hello World\n") {
+		t.Errorf("DiffRevisions() got = %s", diff)
+	}
+
+	missing, _, err := client.Flow.GetRevision(ctx, "tutorial", "hello_world", 3)
+	if err != nil || missing != nil {
+		t.Errorf("GetRevision() of a missing revision got = %v, error = %v", missing, err)
+	}
+}
+
+func TestServer_Dependencies(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client := server.Client()
+	ctx := context.Background()
+
+	if _, err := server.AddFlow(helloWorld); err != nil {
+		t.Fatalf("AddFlow() error = %v", err)
+	}
+	parent := "id: parent\nnamespace: tutorial\ntasks:\n  - id: run\n    type: io.kestra.plugin.core.flow.Subflow\n    namespace: tutorial\n    flowId: hello_world\n"
+	if _, err := server.AddFlow(parent); err != nil {
+		t.Fatalf("AddFlow() error = %v", err)
+	}
+
+	graph, _, err := client.Flow.Dependencies(ctx, "tutorial", "hello_world", false)
+	if err != nil || len(graph.Nodes) != 2 || len(graph.Edges) != 1 {
+		t.Fatalf("Dependencies() got = %v, error = %v", graph, err)
+	}
+	dependents := graph.Dependents(kestra.FlowRef{Namespace: "tutorial", ID: "hello_world"})
+	if len(dependents) != 1 || dependents[0].ID != "parent" {
+		t.Errorf("Dependents() got = %v", dependents)
+	}
+
+	missing, _, err := client.Flow.Dependencies(ctx, "tutorial", "missing", false)
+	if err != nil || missing != nil {
+		t.Errorf("Dependencies() of a missing flow got = %v, error = %v", missing, err)
+	}
+}
+
+func TestServer_Graph(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client := server.Client()
+	ctx := context.Background()
+
+	if _, err := server.AddFlow(helloWorld); err != nil {
+		t.Fatalf("AddFlow() error = %v", err)
+	}
+
+	graph, _, err := client.Flow.Graph(ctx, "tutorial", "hello_world")
+	if err != nil {
+		t.Fatalf("Graph() error = %v", err)
+	}
+	if mermaid := graph.Mermaid(); !strings.Contains(mermaid, "n0 --> n1\n") || !strings.Contains(mermaid, `n1["log"]`) {
+		t.Errorf("Mermaid() got = %s", mermaid)
+	}
+
+	missing, _, err := client.Flow.Graph(ctx, "tutorial", "missing")
+	if err != nil || missing != nil {
+		t.Errorf("Graph() of a missing flow got = %v, error = %v", missing, err)
+	}
+}
+
+func TestServer_Upsert(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client := server.Client()
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		source       string
+		wantAction   kestra.SyncAction
+		wantRevision string
+	}{
+		{"should create", helloWorld, kestra.SyncCreate, "1"},
+		{"should leave an unchanged flow", helloWorld, kestra.SyncUnchanged, "1"},
+		{"should update", strings.Replace(helloWorld, "Hello World", "Hello", 1), kestra.SyncUpdate, "2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _, err := client.Flow.Upsert(ctx, tt.source)
+			if err != nil {
+				t.Fatalf("Upsert() error = %v", err)
+			}
+			if result.Action != tt.wantAction || result.Flow.Revision.String() != tt.wantRevision {
+				t.Errorf("Upsert() got action %v, revision %v, want %v, %v", result.Action, result.Flow.Revision, tt.wantAction, tt.wantRevision)
+			}
+		})
+	}
+
+	flow, _, err := client.Flow.CreateFromFlow(ctx, &kestra.Flow{ID: "built", Namespace: "tutorial", Tasks: []kestra.FlowTask{kestra.NewTask("log", "io.kestra.plugin.core.log.Log")}})
+	if err != nil || flow.Revision != "1" {
+		t.Fatalf("CreateFromFlow() got = %v, error = %v", flow, err)
+	}
+	if source, _, _ := client.Flow.GetSource(ctx, "tutorial", "built"); !strings.HasPrefix(source, "id: built\nnamespace: tutorial\n") {
+		t.Errorf("GetSource() of a flow created from its model got = %q", source)
+	}
+}
+
+// raceTransport creates the flow of source on the server right before the first flow creation it forwards.
+type raceTransport struct {
+	server *Server
+	source string
+	raced  bool
+}
+
+func (rt *raceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !rt.raced && req.Method == http.MethodPost && req.URL.Path == "/api/v1/flows" {
+		rt.raced = true
+		if _, err := rt.server.AddFlow(rt.source); err != nil {
+			return nil, err
+		}
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestServer_UpsertRace(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	other := strings.Replace(helloWorld, "Hello World", "Created concurrently", 1)
+	client, err := kestra.NewClient(server.URL, &http.Client{Transport: &raceTransport{server: server, source: other}})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	ctx := context.Background()
+
+	result, _, err := client.Flow.Upsert(ctx, helloWorld)
+	if err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	if result.Action != kestra.SyncUpdate || result.Flow.Revision.String() != "2" {
+		t.Errorf("Upsert() got action %v, revision %v, want %v, 2", result.Action, result.Flow.Revision, kestra.SyncUpdate)
+	}
+	if source, _, _ := client.Flow.GetSource(ctx, "tutorial", "hello_world"); strings.TrimSpace(source) != strings.TrimSpace(helloWorld) {
+		t.Errorf("GetSource() after a raced Upsert got = %q, want %q", source, helloWorld)
+	}
+}
+
+func TestServer_DisableFlows(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client := server.Client()
+	ctx := context.Background()
+
+	if _, err := server.AddFlow(helloWorld); err != nil {
+		t.Fatalf("AddFlow() error = %v", err)
+	}
+
+	result, _, err := client.Flow.SetDisabledByQuery(ctx, &kestra.FlowFilter{Namespace: "tutorial"}, true)
+	if err != nil || result.Count != 1 || result.Outcomes[0].Status != kestra.FlowDisabled {
+		t.Errorf("SetDisabledByQuery() got = %+v, error = %v", result, err)
+	}
+	if flow, _ := server.Flow("tutorial", "hello_world"); !flow.Disabled {
+		t.Errorf("flow is not disabled")
+	}
+
+	outcome, _, err := client.Flow.Disable(ctx, "tutorial", "hello_world")
+	if err != nil || outcome.Status != kestra.FlowUnchanged {
+		t.Errorf("Disable() got = %+v, error = %v", outcome, err)
+	}
+	outcome, _, err = client.Flow.Enable(ctx, "tutorial", "hello_world")
+	if err != nil || outcome.Status != kestra.FlowEnabled {
+		t.Errorf("Enable() got = %+v, error = %v", outcome, err)
+	}
+	if flow, _ := server.Flow("tutorial", "hello_world"); flow.Disabled {
+		t.Errorf("flow is not enabled")
+	}
+}
+
+func TestServer_ExportImport(t *testing.T) {
+	source := NewServer()
+	defer source.Close()
+	target := NewServer()
+	defer target.Close()
+
+	ctx := context.Background()
+	if _, err := source.AddFlow(helloWorld); err != nil {
+		t.Fatalf("AddFlow() error = %v", err)
+	}
+
+	var archive bytes.Buffer
+	if _, err := source.Client().Flow.ExportByQuery(ctx, &kestra.FlowFilter{Namespace: "tutorial"}, &archive); err != nil {
+		t.Fatalf("ExportByQuery() error = %v", err)
+	}
+	if _, err := target.Client().Flow.Import(ctx, &archive); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	flow, ok := target.Flow("tutorial", "hello_world")
+	if !ok || flow.Source != helloWorld {
+		t.Errorf("imported flow = %v", flow)
+	}
+}
+
+func TestServer_DeleteFlows(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client := server.Client()
+	ctx := context.Background()
+
+	for _, source := range []string{
+		helloWorld,
+		"id: dev\nnamespace: tutorial\nlabels:\n  env: dev\ntasks:\n  - id: log\n    type: io.kestra.plugin.core.log.Log\n",
+		"id: prod\nnamespace: tutorial\nlabels:\n  env: prod\ntasks:\n  - id: log\n    type: io.kestra.plugin.core.log.Log\n",
+		"id: other\nnamespace: company.team\ntasks:\n  - id: log\n    type: io.kestra.plugin.core.log.Log\n",
+	} {
+		if _, err := server.AddFlow(source); err != nil {
+			t.Fatalf("AddFlow() error = %v", err)
+		}
+	}
+
+	if _, err := client.Flow.Delete(ctx, "tutorial", "hello_world"); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+	if _, err := client.Flow.Delete(ctx, "tutorial", "hello_world"); !errors.Is(err, kestra.ErrNotFound) {
+		t.Errorf("Delete() of a deleted flow error = %v, want %v", err, kestra.ErrNotFound)
+	}
+
+	byQuery, _, err := client.Flow.DeleteByQuery(ctx, &kestra.FlowFilter{Namespace: "tutorial", Labels: map[string]string{"env": "dev"}})
+	if err != nil || byQuery.Count != 1 || len(byQuery.Outcomes) != 1 || byQuery.Outcomes[0].ID != "dev" {
+		t.Errorf("DeleteByQuery() got = %+v, error = %v", byQuery, err)
+	}
+
+	byIDs, _, err := client.Flow.DeleteByIDs(ctx, []kestra.FlowRef{{Namespace: "tutorial", ID: "prod"}, {Namespace: "tutorial", ID: "dev"}})
+	if err != nil || byIDs.Count != 1 || byIDs.Outcomes[1].Status != kestra.FlowUnknown {
+		t.Errorf("DeleteByIDs() got = %+v, error = %v", byIDs, err)
+	}
+
+	if flows := server.Flows(); len(flows) != 1 || flows[0].ID != "other" {
+		t.Errorf("Flows() got = %v, want only other", flows)
+	}
+}
+
+func TestServer_Executions(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	if _, err := server.AddFlow(helloWorld); err != nil {
+		t.Fatalf("AddFlow() error = %v", err)
+	}
+	server.SetExecutionStates("tutorial", "hello_world", "CREATED", "RUNNING", "FAILED")
+
+	client := server.Client()
+	ctx := context.Background()
+
+	execution, _, err := client.Execution.Create(ctx, "tutorial", "hello_world", map[string]string{"name": "go"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// each Get moves the execution to its next state, until the last one
+	states := []string{execution.State.Current}
+	for i := 0; i < 3; i++ {
+		execution, _, err = client.Execution.Get(ctx, execution.ID)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		states = append(states, execution.State.Current)
+	}
+	if want := []string{"CREATED", "RUNNING", "FAILED", "FAILED"}; !reflect.DeepEqual(states, want) {
+		t.Errorf("states = %v, want %v", states, want)
+	}
+	if len(execution.State.History) != 3 || execution.TaskRunList[0].State.Current != "FAILED" {
+		t.Errorf("execution = %v", execution)
+	}
+
+	logs, _, err := client.Log.Get(ctx, execution.ID)
+	if err != nil || len(*logs) == 0 {
+		t.Fatalf("Log.Get() got = %v, error = %v", logs, err)
+	}
+	if (*logs)[0].Message != "Execution "+execution.ID+" is CREATED" {
+		t.Errorf("first log = %v", (*logs)[0].Message)
+	}
+
+	found := 0
+	for e, err := range client.Execution.SearchAll(ctx, &kestra.ExecutionSearchOptions{Namespace: "tutorial", State: []string{"FAILED"}}) {
+		if err != nil {
+			t.Fatalf("SearchAll() error = %v", err)
+		}
+		if e.ID == execution.ID {
+			found++
+		}
+	}
+	if found != 1 {
+		t.Errorf("SearchAll() found %d executions, want 1", found)
+	}
+
+	_, _, err = client.Execution.Create(ctx, "tutorial", "missing", nil)
+	if !errors.Is(err, kestra.ErrNotFound) {
+		t.Errorf("Create() on a missing flow error = %v, want %v", err, kestra.ErrNotFound)
+	}
+}
+
+func TestServer_Tenant(t *testing.T) {
+	server := NewServer(WithVersion("0.23.0"))
+	defer server.Close()
+
+	if _, err := server.AddFlow(helloWorld); err != nil {
+		t.Fatalf("AddFlow() error = %v", err)
+	}
+
+	client := server.Client(kestra.WithTenant("main"))
+	info, _, err := client.ServerInfo(context.Background())
+	if err != nil || !info.TenantRequired {
+		t.Fatalf("ServerInfo() got = %v, error = %v", info, err)
+	}
+
+	flow, _, err := client.Flow.Get(context.Background(), "tutorial", "hello_world")
+	if err != nil || flow == nil {
+		t.Fatalf("Get() got = %v, error = %v", flow, err)
+	}
+	server.AssertRequested(t, http.MethodGet, "/api/v1/main/flows/tutorial/hello_world")
+}
@@ -2,7 +2,7 @@ package v1
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"net/http"
 	"time"
 )
@@ -21,8 +21,10 @@ type Log struct {
 	Message     string    `json:"message,omitempty" structs:"message,omitempty"`
 }
 
+// Get returns the logs of an execution.
+// A missing execution is not an error: nil logs are returned along with the 404 response.
 func (s *LogService) Get(ctx context.Context, executionID string) (*[]Log, *Response, error) {
-	apiEndpoint := fmt.Sprintf("/api/v1/logs/%s", executionID)
+	apiEndpoint := s.client.apiPath(ctx, "logs/%s", executionID)
 	req, err := s.client.NewRequest(ctx, http.MethodGet, apiEndpoint, nil, "")
 	if err != nil {
 		return nil, nil, err
@@ -30,6 +32,12 @@ func (s *LogService) Get(ctx context.Context, executionID string) (*[]Log, *Resp
 
 	log := new([]Log)
 	resp, err := s.client.Do(req, log)
+	if errors.Is(err, ErrNotFound) {
+		return nil, resp, nil
+	}
+	if err != nil {
+		return nil, resp, err
+	}
 
 	return log, resp, nil
 }
@@ -2,28 +2,53 @@ package v1
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestLogService_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/logs/1CcnlV1DwvXXZauauyirIO", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testRequestURL(t, r, "/api/v1/logs/1CcnlV1DwvXXZauauyirIO")
+
+		fmt.Fprint(w, `[{"namespace":"tutorial","flowId":"hello_world","taskId":"log","executionId":"1CcnlV1DwvXXZauauyirIO","taskRunId":"321HwiEUBACDQzkJcP8J4r","timestamp":"2024-07-15T09:27:24.175Z","level":"INFO","message":"Hello World"}]`)
+	})
+
 	type args struct {
 		ctx         context.Context
 		executionID string
 	}
 	tests := []struct {
 		name    string
-		s       LogService
+		s       LogAPI
 		args    args
-		want    *Execution
-		want1   *Response
+		want    *[]Log
+		code    int
 		wantErr bool
 	}{
-		// TODO: Add test cases.
+		{"should get logs", testClient.Log,
+			args{context.Background(), "1CcnlV1DwvXXZauauyirIO"},
+			&[]Log{{"log", "tutorial", "hello_world", "1CcnlV1DwvXXZauauyirIO", "321HwiEUBACDQzkJcP8J4r",
+				time.Date(2024, 7, 15, 9, 27, 24, 175000000, time.UTC), "INFO", "Hello World"}},
+			200,
+			false,
+		},
+		{"should not find anything", testClient.Log,
+			args{context.Background(), "unknown"},
+			nil,
+			404,
+			false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, got1, err := tt.s.Get(tt.args.ctx, tt.args.executionID)
+			got, resp, err := tt.s.Get(tt.args.ctx, tt.args.executionID)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -31,8 +56,8 @@ func TestLogService_Get(t *testing.T) {
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Get() got = %v, want %v", got, tt.want)
 			}
-			if !reflect.DeepEqual(got1, tt.want1) {
-				t.Errorf("Get() got1 = %v, want %v", got1, tt.want1)
+			if !reflect.DeepEqual(resp.StatusCode, tt.code) {
+				t.Errorf("StatusCode got = %v, want %v", resp.StatusCode, tt.code)
 			}
 		})
 	}
@@ -0,0 +1,121 @@
+package v1
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ClientOption configures a Client created by NewClient.
+type ClientOption func(*Client) error
+
+// WithBasicAuth authenticates every request with HTTP basic authentication,
+// as configured by "kestra.server.basic-auth" on an OSS server.
+func WithBasicAuth(username, password string) ClientOption {
+	return func(c *Client) error {
+		credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+		c.headers.Set("Authorization", "Basic "+credentials)
+		return nil
+	}
+}
+
+// WithBearerToken authenticates every request with the given bearer token, such as a JWT.
+func WithBearerToken(token string) ClientOption {
+	return func(c *Client) error {
+		c.headers.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+}
+
+// WithAPIToken authenticates every request with a Kestra Enterprise API token,
+// created for a user or a service account.
+func WithAPIToken(token string) ClientOption {
+	return WithBearerToken(token)
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) error {
+		c.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithTimeout sets the time limit of each request, including reading the response body.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.client.Timeout = timeout
+		return nil
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used to connect to Kestra, for instance to trust
+// a custom certificate authority or to present a client certificate.
+// The http.Client given to NewClient must use an *http.Transport, or no transport at all.
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(c *Client) error {
+		var transport *http.Transport
+		switch t := c.client.Transport.(type) {
+		case nil:
+			transport = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			transport = t.Clone()
+		default:
+			return errors.New("kestra: WithTLSConfig requires the http.Client to use an *http.Transport")
+		}
+
+		transport.TLSClientConfig = config.Clone()
+		c.client.Transport = transport
+		return nil
+	}
+}
+
+// WithHeader adds a header sent with every request. It can be used multiple times.
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) error {
+		c.headers.Add(key, value)
+		return nil
+	}
+}
+
+// WithTenant routes every request through the given tenant. See Client.Tenant.
+func WithTenant(tenant string) ClientOption {
+	return func(c *Client) error {
+		c.Tenant = tenant
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the RetryPolicy of the client.
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		c.RetryPolicy = policy
+		return nil
+	}
+}
+
+// WithFlowAPI replaces the flow service of the client, e.g. with a mock from package kestramock.
+func WithFlowAPI(flow FlowAPI) ClientOption {
+	return func(c *Client) error {
+		c.Flow = flow
+		return nil
+	}
+}
+
+// WithExecutionAPI replaces the execution service of the client.
+func WithExecutionAPI(execution ExecutionAPI) ClientOption {
+	return func(c *Client) error {
+		c.Execution = execution
+		return nil
+	}
+}
+
+// WithLogAPI replaces the log service of the client.
+func WithLogAPI(log LogAPI) ClientOption {
+	return func(c *Client) error {
+		c.Log = log
+		return nil
+	}
+}
@@ -0,0 +1,111 @@
+package v1
+
+import (
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+// defaultPageSize is the page size used by iterators when none is requested.
+const defaultPageSize = 50
+
+// ListOptions specifies the optional paging and sorting parameters of methods that support pagination.
+type ListOptions struct {
+	// Page of results to retrieve, starting at 1.
+	Page int
+	// Size of the page of results to retrieve.
+	Size int
+	// Sort fields, formatted as "field:asc" or "field:desc" (e.g. "id:asc").
+	Sort []string
+}
+
+// SortDirection is the direction of a sort field of ListOptions.
+type SortDirection string
+
+const (
+	// SortAsc sorts in ascending order.
+	SortAsc SortDirection = "asc"
+	// SortDesc sorts in descending order.
+	SortDesc SortDirection = "desc"
+)
+
+// SortBy returns a sort field of ListOptions, e.g. "id:desc" for SortBy("id", SortDesc).
+func SortBy(field string, direction SortDirection) string {
+	return field + ":" + string(direction)
+}
+
+// addValues adds the paging parameters to query.
+func (o *ListOptions) addValues(query url.Values) {
+	if o == nil {
+		return
+	}
+	if o.Page > 0 {
+		query.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.Size > 0 {
+		query.Set("size", strconv.Itoa(o.Size))
+	}
+	for _, sort := range o.Sort {
+		query.Add("sort", sort)
+	}
+}
+
+// PagedResults is a page of results returned by a Kestra search endpoint.
+type PagedResults[T any] struct {
+	Results []T `json:"results,omitempty" structs:"results,omitempty"`
+	Total   int `json:"total,omitempty" structs:"total,omitempty"`
+}
+
+func (p *PagedResults[T]) total() int {
+	if p == nil {
+		return 0
+	}
+	return p.Total
+}
+
+// pagedResults is implemented by every PagedResults instantiation, so that
+// Response can populate its paging values without knowing the result type.
+type pagedResults interface {
+	total() int
+}
+
+// paginate returns an iterator over every result of a paginated API method.
+// fetch is called for each page, starting at the page given by opts, until all results
+// have been returned. Iteration stops after yielding the first error.
+func paginate[T any](opts *ListOptions, fetch func(opts *ListOptions) (*PagedResults[T], *Response, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		page := ListOptions{}
+		if opts != nil {
+			page = *opts
+		}
+		if page.Page < 1 {
+			page.Page = 1
+		}
+		if page.Size < 1 {
+			page.Size = defaultPageSize
+		}
+
+		for {
+			result, _, err := fetch(&page)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if result == nil {
+				return
+			}
+
+			for _, item := range result.Results {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if len(result.Results) < page.Size || page.Page*page.Size >= result.Total {
+				return
+			}
+			page.Page++
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestListOptions_addValues(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *ListOptions
+		want url.Values
+	}{
+		{"nil options", nil, url.Values{}},
+		{"empty options", &ListOptions{}, url.Values{}},
+		{"all options", &ListOptions{Page: 2, Size: 10, Sort: []string{"id:asc", "namespace:desc"}},
+			url.Values{"page": {"2"}, "size": {"10"}, "sort": {"id:asc", "namespace:desc"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := url.Values{}
+			tt.opts.addValues(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("addValues() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlowService_SearchAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	ids := []string{"a", "b", "c", "d", "e"}
+	testMux.HandleFunc("/api/v1/flows/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+
+		params := r.URL.Query()
+		if params.Get("q") == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var page, size int
+		fmt.Sscan(params.Get("page"), &page)
+		fmt.Sscan(params.Get("size"), &size)
+		from, to := min((page-1)*size, len(ids)), min(page*size, len(ids))
+
+		results := []string{}
+		for _, id := range ids[from:to] {
+			results = append(results, fmt.Sprintf(`{"id":%q,"namespace":"tutorial"}`, id))
+		}
+		fmt.Fprintf(w, `{"results":[%s],"total":%d}`, strings.Join(results, ","), len(ids))
+	})
+
+	tests := []struct {
+		name    string
+		query   string
+		opts    *ListOptions
+		limit   int
+		want    []string
+		wantErr bool
+	}{
+		{"should walk every page", "", &ListOptions{Size: 2}, 0, ids, false},
+		{"should start at the requested page", "", &ListOptions{Page: 2, Size: 2}, 0, []string{"c", "d", "e"}, false},
+		{"should use a default page size", "", nil, 0, ids, false},
+		{"should stop when the caller breaks", "", &ListOptions{Size: 2}, 3, []string{"a", "b", "c"}, false},
+		{"should stop at the first error", "fail", &ListOptions{Size: 2}, 0, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &FlowSearchOptions{FlowFilter: FlowFilter{Query: tt.query}}
+			if tt.opts != nil {
+				opts.ListOptions = *tt.opts
+			}
+
+			var got []string
+			var gotErr error
+			for flow, err := range testClient.Flow.SearchAll(context.Background(), opts) {
+				if err != nil {
+					gotErr = err
+					continue
+				}
+				got = append(got, flow.ID)
+				if len(got) == tt.limit {
+					break
+				}
+			}
+			if (gotErr != nil) != tt.wantErr {
+				t.Errorf("SearchAll() error = %v, wantErr %v", gotErr, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(gotErr, ErrServer) {
+				t.Errorf("SearchAll() error = %v, want %v", gotErr, ErrServer)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchAll() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	flows := testClient.Flow.SearchAll(context.Background(), &FlowSearchOptions{ListOptions: ListOptions{Size: 2}})
+	for range 2 {
+		var got []string
+		for flow, err := range flows {
+			if err != nil {
+				t.Fatalf("SearchAll() error = %v", err)
+			}
+			got = append(got, flow.ID)
+		}
+		if !reflect.DeepEqual(got, ids) {
+			t.Errorf("SearchAll() reused got = %v, want %v", got, ids)
+		}
+	}
+}
+
+func TestResponse_pageValues(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/flows/search", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"results":[{"id":"c","namespace":"tutorial"},{"id":"d","namespace":"tutorial"}],"total":5}`)
+	})
+
+	_, resp, err := testClient.Flow.Search(context.Background(), &FlowSearchOptions{ListOptions: ListOptions{Page: 2, Size: 2}})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if resp.StartAt != 2 || resp.MaxResults != 2 || resp.Total != 5 {
+		t.Errorf("Response got StartAt = %d, MaxResults = %d, Total = %d, want 2, 2, 5", resp.StartAt, resp.MaxResults, resp.Total)
+	}
+}
@@ -0,0 +1,111 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// unmarshalWithExtra decodes data into v, a pointer to a struct without JSON methods (usually an alias
+// type), and returns the properties of data that are not fields of the struct, or nil if there are none.
+// Numbers of the returned properties are decoded as json.Number to keep their exact value.
+func unmarshalWithExtra(data []byte, v interface{}) (map[string]interface{}, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	var all map[string]interface{}
+	if err := decodeJSON(data, &all); err != nil {
+		return nil, err
+	}
+	for _, name := range jsonFieldNames(reflect.TypeOf(v).Elem()) {
+		delete(all, name)
+	}
+	if len(all) == 0 {
+		return nil, nil
+	}
+
+	return all, nil
+}
+
+// marshalWithExtra encodes v, a struct without JSON methods (usually an alias type), adding the
+// properties of extra that are not fields of the struct. Extra properties are sorted by name.
+func marshalWithExtra(v interface{}, extra map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	known := make(map[string]bool)
+	typ := reflect.TypeOf(v)
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	for _, name := range jsonFieldNames(typ) {
+		known[name] = true
+	}
+
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		if !known[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.Write(bytes.TrimSuffix(data, []byte("}")))
+	for _, name := range names {
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(extra[name])
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// decodeJSON decodes data into v, using json.Number for numbers.
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// jsonFieldNames returns the JSON names of the fields of the struct type typ.
+func jsonFieldNames(typ reflect.Type) []string {
+	var names []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				names = append(names, jsonFieldNames(field.Type)...)
+				continue
+			}
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
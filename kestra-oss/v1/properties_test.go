@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalWithExtra(t *testing.T) {
+	type known struct {
+		ID   string `json:"id"`
+		Size int    `json:"size,omitempty"`
+		Skip string `json:"-"`
+	}
+
+	tests := []struct {
+		name      string
+		data      string
+		want      known
+		wantExtra map[string]interface{}
+	}{
+		{"no extra", `{"id":"a","size":2}`, known{ID: "a", Size: 2}, nil},
+		{"extra keys", `{"id":"a","format":"json","max":1.50,"nested":{"n":1}}`, known{ID: "a"},
+			map[string]interface{}{"format": "json", "max": json.Number("1.50"), "nested": map[string]interface{}{"n": json.Number("1")}}},
+		{"ignored field is extra", `{"id":"a","Skip":"x"}`, known{ID: "a"}, map[string]interface{}{"Skip": "x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got known
+			extra, err := unmarshalWithExtra([]byte(tt.data), &got)
+			if err != nil {
+				t.Fatalf("unmarshalWithExtra() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("unmarshalWithExtra() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(extra, tt.wantExtra) {
+				t.Errorf("unmarshalWithExtra() extra = %v, want %v", extra, tt.wantExtra)
+			}
+		})
+	}
+}
+
+func TestMarshalWithExtra(t *testing.T) {
+	type known struct {
+		ID   string `json:"id,omitempty"`
+		Size int    `json:"size,omitempty"`
+	}
+
+	tests := []struct {
+		name  string
+		v     known
+		extra map[string]interface{}
+		want  string
+	}{
+		{"no extra", known{ID: "a"}, nil, `{"id":"a"}`},
+		{"sorted extra", known{ID: "a"}, map[string]interface{}{"z": 1, "b": json.Number("1.50")}, `{"id":"a","b":1.50,"z":1}`},
+		{"empty struct", known{}, map[string]interface{}{"b": true}, `{"b":true}`},
+		{"known fields win", known{ID: "a"}, map[string]interface{}{"id": "b"}, `{"id":"a"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := marshalWithExtra(tt.v, tt.extra)
+			if err != nil {
+				t.Fatalf("marshalWithExtra() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("marshalWithExtra() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMinBackoff = 500 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// defaultRetryStatusCodes are the transient status codes retried when RetryPolicy.StatusCodes is empty.
+var defaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy configures how the Client retries requests that failed with a transient error,
+// such as a transport error or a 502/503 response while Kestra restarts.
+// Retries are disabled unless a policy is set on Client.RetryPolicy.
+//
+// Only idempotent methods (GET, HEAD, OPTIONS, PUT, DELETE) are retried by default, since retrying
+// a POST may for instance start the same execution twice. Use ContextWithRetry to retry a call anyway.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values lower than 2 disable retries.
+	MaxAttempts int
+	// MinBackoff is the base delay before the first retry; it doubles for each following retry.
+	// Defaults to 500ms.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts, including the delay requested by a Retry-After header.
+	// Defaults to 30s.
+	MaxBackoff time.Duration
+	// StatusCodes are the response status codes that are retried.
+	// Defaults to 429, 502, 503 and 504.
+	StatusCodes []int
+}
+
+// DefaultRetryPolicy returns a RetryPolicy making up to maxAttempts attempts with the default backoff.
+func DefaultRetryPolicy(maxAttempts int) *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: maxAttempts,
+		MinBackoff:  defaultMinBackoff,
+		MaxBackoff:  defaultMaxBackoff,
+		StatusCodes: slices.Clone(defaultRetryStatusCodes),
+	}
+}
+
+type retryContextKey struct{}
+
+// ContextWithRetry returns a copy of ctx marking the requests made with it as safe to retry,
+// whatever their method. It is meant for calls such as ExecutionService.Create where the
+// caller accepts the risk of a duplicate when a response is lost.
+func ContextWithRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryContextKey{}, true)
+}
+
+// retryable reports whether req may be sent more than once.
+func (p *RetryPolicy) retryable(req *http.Request) bool {
+	if p == nil || p.MaxAttempts < 2 {
+		return false
+	}
+	// a body that cannot be replayed can only be sent once
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	forced, _ := req.Context().Value(retryContextKey{}).(bool)
+	return forced
+}
+
+// shouldRetry reports whether the outcome of an attempt is a transient failure.
+func (p *RetryPolicy) shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+
+	codes := p.StatusCodes
+	if len(codes) == 0 {
+		codes = defaultRetryStatusCodes
+	}
+	return slices.Contains(codes, resp.StatusCode)
+}
+
+// backoff returns the delay to wait after the given failed attempt (starting at 1).
+// A Retry-After header sent with resp takes precedence over the exponential backoff.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	minBackoff, maxBackoff := p.MinBackoff, p.MaxBackoff
+	if minBackoff <= 0 {
+		minBackoff = defaultMinBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return min(delay, maxBackoff)
+		}
+	}
+
+	delay := minBackoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxBackoff)
+
+	// equal jitter: wait at least half of the delay, so that concurrent clients spread their retries
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// parseRetryAfter parses the value of a Retry-After header, either a number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// send sends req with the underlying http.Client, retrying transient failures
+// according to c.RetryPolicy.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	policy := c.RetryPolicy
+	if !policy.retryable(req) {
+		return c.client.Do(req)
+	}
+
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := c.client.Do(req)
+		if attempt >= policy.MaxAttempts || !policy.shouldRetry(ctx, resp, err) {
+			return resp, err
+		}
+
+		delay := policy.backoff(attempt, resp)
+		if resp != nil {
+			// drain the body so that the connection can be reused
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		req = req.Clone(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_Retry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var attempts atomic.Int32
+	var bodies []string
+	testMux.HandleFunc("/api/v1/flows/tutorial/hello_world", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if attempts.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"id":"hello_world","namespace":"tutorial"}`)
+	})
+	testMux.HandleFunc("/api/v1/executions/tutorial/hello_world", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{"id":"1CcnlV1DwvXXZauauyirIO"}`)
+	})
+	testMux.HandleFunc("/api/v1/flows/tutorial/broken", func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	tests := []struct {
+		name         string
+		policy       *RetryPolicy
+		call         func() error
+		wantAttempts int32
+		wantErr      error
+	}{
+		{"should not retry without policy", nil, func() error {
+			_, _, err := testClient.Flow.Update(context.Background(), "tutorial", "hello_world", "id: hello_world")
+			return err
+		}, 1, ErrServer},
+		{"should retry idempotent methods and replay the body", &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}, func() error {
+			_, _, err := testClient.Flow.Update(context.Background(), "tutorial", "hello_world", "id: hello_world")
+			return err
+		}, 3, nil},
+		{"should stop after max attempts", &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}, func() error {
+			_, _, err := testClient.Flow.Get(context.Background(), "tutorial", "broken")
+			return err
+		}, 2, ErrServer},
+		{"should not retry POST by default", &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}, func() error {
+			_, _, err := testClient.Execution.Create(context.Background(), "tutorial", "hello_world", map[string]string{"name": "go"})
+			return err
+		}, 1, ErrServer},
+		{"should retry POST when forced", &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}, func() error {
+			_, _, err := testClient.Execution.Create(ContextWithRetry(context.Background()), "tutorial", "hello_world", map[string]string{"name": "go"})
+			return err
+		}, 3, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts.Store(0)
+			bodies = nil
+			testClient.RetryPolicy = tt.policy
+
+			err := tt.call()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			for _, body := range bodies {
+				if body != bodies[0] {
+					t.Errorf("replayed body = %q, want %q", body, bodies[0])
+				}
+			}
+		})
+	}
+}
+
+func TestClient_RetryContextCanceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/flows/tutorial/hello_world", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	testClient.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MaxBackoff: time.Minute}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err := testClient.Flow.Get(ctx, "tutorial", "hello_world")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := &RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter string
+		min        time.Duration
+		max        time.Duration
+	}{
+		{"first retry", 1, "", 50 * time.Millisecond, 100 * time.Millisecond},
+		{"third retry", 3, "", 200 * time.Millisecond, 400 * time.Millisecond},
+		{"capped retry", 10, "", 500 * time.Millisecond, time.Second},
+		{"retry after seconds", 1, "0", 0, 0},
+		{"retry after capped", 1, "120", time.Second, time.Second},
+		{"retry after date in the past", 1, "Wed, 21 Oct 2015 07:28:00 GMT", 0, 0},
+		{"invalid retry after", 1, "soon", 50 * time.Millisecond, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+			if got := policy.backoff(tt.attempt, resp); got < tt.min || got > tt.max {
+				t.Errorf("backoff() = %v, want between %v and %v", got, tt.min, tt.max)
+			}
+		})
+	}
+}
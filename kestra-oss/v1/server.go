@@ -0,0 +1,188 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tenantRoutesVersion is the first OSS version routing the API through a tenant ("main").
+const tenantRoutesVersion = "0.23.0"
+
+// The first versions serving the endpoints that older supported servers answer with a 404.
+const (
+	flowValidationVersion     = "0.7.0"  // flows/validate
+	fragmentValidationVersion = "0.13.0" // flows/validate/task
+	bulkFlowsVersion          = "0.9.0"  // flows/delete, flows/enable, flows/disable, flows/export and flows/import
+	flowGraphVersion          = "0.6.0"  // flows/{namespace}/{id}/graph
+	flowDependenciesVersion   = "0.14.0" // flows/{namespace}/{id}/dependencies
+)
+
+// ServerInfo describes a Kestra server, as reported by its configs endpoint.
+type ServerInfo struct {
+	Version    string `json:"version,omitempty" structs:"version,omitempty"`
+	CommitID   string `json:"commitId,omitempty" structs:"commitId,omitempty"`
+	CommitDate string `json:"commitDate,omitempty" structs:"commitDate,omitempty"`
+	// Edition is "OSS" or "EE".
+	Edition string `json:"edition,omitempty" structs:"edition,omitempty"`
+	UUID    string `json:"uuid,omitempty" structs:"uuid,omitempty"`
+	URL     string `json:"url,omitempty" structs:"url,omitempty"`
+
+	// Features holds the "is...Enabled" flags of the configuration, keyed by feature name
+	// (e.g. "basicAuth" for "isBasicAuthEnabled").
+	Features map[string]bool `json:"-" structs:"-"`
+	// TenantRequired reports whether the server only serves the tenant-aware API routes,
+	// in which case Client.Tenant must be set.
+	TenantRequired bool `json:"-" structs:"-"`
+}
+
+// UnmarshalJSON decodes the configs endpoint response, collecting the feature flags.
+func (i *ServerInfo) UnmarshalJSON(data []byte) error {
+	type serverInfo ServerInfo
+	if err := json.Unmarshal(data, (*serverInfo)(i)); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	i.Features = make(map[string]bool)
+	for key, value := range fields {
+		if !strings.HasPrefix(key, "is") || !strings.HasSuffix(key, "Enabled") || len(key) <= len("isEnabled") {
+			continue
+		}
+		var enabled bool
+		if err := json.Unmarshal(value, &enabled); err != nil {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key, "is"), "Enabled")
+		first, size := utf8.DecodeRuneInString(name)
+		i.Features[string(unicode.ToLower(first))+name[size:]] = enabled
+	}
+
+	i.TenantRequired = i.Edition != "EE" && i.AtLeast(tenantRoutesVersion)
+
+	return nil
+}
+
+// AtLeast reports whether the server version is greater than or equal to version (e.g. "0.18.0").
+// Pre-release suffixes such as "-SNAPSHOT" are ignored.
+func (i *ServerInfo) AtLeast(version string) bool {
+	return compareVersions(i.Version, version) >= 0
+}
+
+// compareVersions compares two "major.minor.patch" versions, returning -1, 0 or 1.
+func compareVersions(a, b string) int {
+	va, vb := parseVersion(a), parseVersion(b)
+	for n := range va {
+		if va[n] != vb[n] {
+			if va[n] < vb[n] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func parseVersion(version string) [3]int {
+	var parsed [3]int
+	version = strings.TrimPrefix(version, "v")
+	if n := strings.IndexAny(version, "-+"); n >= 0 {
+		version = version[:n]
+	}
+	for n, part := range strings.SplitN(version, ".", 3) {
+		parsed[n], _ = strconv.Atoi(part)
+	}
+	return parsed
+}
+
+// ServerInfo returns the version, edition and features of the Kestra server.
+// The result is fetched once and cached: service methods use it to fail fast with an
+// *UnsupportedError when they are not supported by the server, instead of an opaque 404.
+// Those checks are only made once ServerInfo has been called, e.g. at startup.
+func (c *Client) ServerInfo(ctx context.Context) (*ServerInfo, *Response, error) {
+	if cached := c.cachedServerInfo(); cached != nil {
+		return cached.clone(), nil, nil
+	}
+
+	// the configs endpoint is never routed through a tenant
+	req, err := c.NewRequest(ctx, http.MethodGet, "/api/v1/configs", nil, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := new(ServerInfo)
+	resp, err := c.Do(req, info)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	c.serverInfoMu.Lock()
+	c.serverInfo = info
+	c.serverInfoMu.Unlock()
+
+	return info.clone(), resp, nil
+}
+
+// clone returns a copy of i that shares nothing with it, so that callers cannot change the cached info.
+func (i *ServerInfo) clone() *ServerInfo {
+	info := *i
+	info.Features = maps.Clone(i.Features)
+	return &info
+}
+
+// cachedServerInfo returns the server info fetched by ServerInfo, or nil.
+func (c *Client) cachedServerInfo() *ServerInfo {
+	c.serverInfoMu.Lock()
+	defer c.serverInfoMu.Unlock()
+	return c.serverInfo
+}
+
+// requireVersion returns an *UnsupportedError if the server is known to be older than minVersion.
+func (c *Client) requireVersion(feature string, minVersion string) error {
+	info := c.cachedServerInfo()
+	if info == nil || info.Version == "" || info.AtLeast(minVersion) {
+		return nil
+	}
+
+	return &UnsupportedError{
+		Feature:       feature,
+		ServerVersion: info.Version,
+		Requirement:   "requires version " + minVersion + " or later",
+	}
+}
+
+// checkRoutes returns an *UnsupportedError if the server is known not to serve the
+// routes (legacy or tenant-aware) requests made with ctx would use.
+func (c *Client) checkRoutes(ctx context.Context) error {
+	info := c.cachedServerInfo()
+	if info == nil || info.Version == "" {
+		return nil
+	}
+
+	tenant := c.tenant(ctx)
+	if tenant == "" && info.TenantRequired {
+		return &UnsupportedError{
+			Feature:       "API routes without tenant",
+			ServerVersion: info.Version,
+			Requirement:   "set a tenant such as \"main\"",
+		}
+	}
+	if tenant != "" && info.Edition != "EE" && !info.AtLeast(tenantRoutesVersion) {
+		return &UnsupportedError{
+			Feature:       "tenant " + strconv.Quote(tenant),
+			ServerVersion: info.Version,
+			Requirement:   "requires version " + tenantRoutesVersion + " or later, or the Enterprise Edition",
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_ServerInfo(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls atomic.Int32
+	testMux.HandleFunc("/api/v1/configs", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		calls.Add(1)
+
+		fmt.Fprint(w, `{"uuid":"4Vx1K8i3Ed4SzGePwFxqXv","version":"0.17.5","commitId":"3f1a2b4","commitDate":"2024-07-02T09:12:45.000Z","isCustomDashboardsEnabled":false,"isTaskRunEnabled":true,"isAnonymousUsageEnabled":true,"isBasicAuthEnabled":false,"url":"http://localhost:8080","edition":"OSS"}`)
+	})
+	testClient.Tenant = "main"
+
+	want := &ServerInfo{
+		Version:    "0.17.5",
+		CommitID:   "3f1a2b4",
+		CommitDate: "2024-07-02T09:12:45.000Z",
+		Edition:    "OSS",
+		UUID:       "4Vx1K8i3Ed4SzGePwFxqXv",
+		URL:        "http://localhost:8080",
+		Features: map[string]bool{
+			"customDashboards": false,
+			"taskRun":          true,
+			"anonymousUsage":   true,
+			"basicAuth":        false,
+		},
+	}
+
+	for n := 0; n < 2; n++ {
+		got, _, err := testClient.ServerInfo(context.Background())
+		if err != nil {
+			t.Fatalf("ServerInfo() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ServerInfo() got = %v, want %v", got, want)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("ServerInfo() calls = %d, want 1", calls.Load())
+	}
+
+	// the server does not know about tenants: requests fail before being sent
+	_, _, err := testClient.Flow.Get(context.Background(), "tutorial", "hello_world")
+	var unsupported *UnsupportedError
+	if !errors.As(err, &unsupported) || !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("Get() error = %v, want *UnsupportedError", err)
+	}
+	if unsupported.ServerVersion != "0.17.5" {
+		t.Errorf("ServerVersion got = %v, want %v", unsupported.ServerVersion, "0.17.5")
+	}
+
+	// callers get their own copy of the cached info
+	got, _, _ := testClient.ServerInfo(context.Background())
+	got.Features["basicAuth"] = true
+	if again, _, _ := testClient.ServerInfo(context.Background()); again.Features["basicAuth"] {
+		t.Errorf("ServerInfo() shares its Features with the cache")
+	}
+
+	if err := testClient.requireVersion("flow validation", "0.18.0"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("requireVersion() error = %v, want %v", err, ErrUnsupported)
+	}
+	if err := testClient.requireVersion("flow search", "0.10.0"); err != nil {
+		t.Errorf("requireVersion() error = %v, want nil", err)
+	}
+}
+
+func TestFlowService_requireVersion(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/v1/configs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"version":"0.5.0","edition":"OSS"}`)
+	})
+	testMux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+	if _, _, err := testClient.ServerInfo(context.Background()); err != nil {
+		t.Fatalf("ServerInfo() error = %v", err)
+	}
+
+	ctx := context.Background()
+	flows := []FlowRef{{Namespace: "tutorial", ID: "hello_world"}}
+	filter := &FlowFilter{Namespace: "tutorial"}
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"Validate", func() error { _, _, err := testClient.Flow.Validate(ctx, "id: a"); return err }},
+		{"ValidateTask", func() error { _, _, err := testClient.Flow.ValidateTask(ctx, "id: a"); return err }},
+		{"ValidateTrigger", func() error { _, _, err := testClient.Flow.ValidateTrigger(ctx, "id: a"); return err }},
+		{"DeleteByIDs", func() error { _, _, err := testClient.Flow.DeleteByIDs(ctx, flows); return err }},
+		{"DeleteByQuery", func() error { _, _, err := testClient.Flow.DeleteByQuery(ctx, filter); return err }},
+		{"SetDisabledByIDs", func() error { _, _, err := testClient.Flow.SetDisabledByIDs(ctx, flows, true); return err }},
+		{"SetDisabledByQuery", func() error { _, _, err := testClient.Flow.SetDisabledByQuery(ctx, filter, true); return err }},
+		{"ExportByQuery", func() error { _, err := testClient.Flow.ExportByQuery(ctx, filter, io.Discard); return err }},
+		{"ExportByIDs", func() error { _, err := testClient.Flow.ExportByIDs(ctx, flows, io.Discard); return err }},
+		{"Import", func() error { _, err := testClient.Flow.Import(ctx, strings.NewReader("id: a")); return err }},
+		{"Dependencies", func() error {
+			_, _, err := testClient.Flow.Dependencies(ctx, "tutorial", "hello_world", false)
+			return err
+		}},
+		{"Graph", func() error { _, _, err := testClient.Flow.Graph(ctx, "tutorial", "hello_world"); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			var unsupported *UnsupportedError
+			if !errors.As(err, &unsupported) || unsupported.ServerVersion != "0.5.0" {
+				t.Errorf("%s() error = %v, want *UnsupportedError", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestClient_checkRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		info    *ServerInfo
+		tenant  string
+		wantErr bool
+	}{
+		{"unknown server", nil, "main", false},
+		{"legacy routes on old server", &ServerInfo{Version: "0.17.5", Edition: "OSS"}, "", false},
+		{"tenant on old OSS server", &ServerInfo{Version: "0.17.5", Edition: "OSS"}, "main", true},
+		{"tenant on EE server", &ServerInfo{Version: "0.17.5", Edition: "EE"}, "main", false},
+		{"tenant on recent server", &ServerInfo{Version: "0.23.2", Edition: "OSS", TenantRequired: true}, "main", false},
+		{"legacy routes on recent server", &ServerInfo{Version: "1.0.0", Edition: "OSS", TenantRequired: true}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := NewClient(testKestraInstanceURL, nil, WithTenant(tt.tenant))
+			c.serverInfo = tt.info
+			if err := c.checkRoutes(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("checkRoutes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServerInfo_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           string
+		tenantRequired bool
+	}{
+		{"old OSS", `{"version":"0.17.5","edition":"OSS"}`, false},
+		{"recent OSS snapshot", `{"version":"0.23.0-SNAPSHOT","edition":"OSS"}`, true},
+		{"recent OSS without edition", `{"version":"1.0.1"}`, true},
+		{"recent EE", `{"version":"1.0.1","edition":"EE"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got ServerInfo
+			if err := got.UnmarshalJSON([]byte(tt.data)); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if got.TenantRequired != tt.tenantRequired {
+				t.Errorf("TenantRequired got = %v, want %v", got.TenantRequired, tt.tenantRequired)
+			}
+		})
+	}
+}
+
+func Test_compareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"0.17.5", "0.17.5", 0},
+		{"0.17.5", "0.18.0", -1},
+		{"0.18.0-SNAPSHOT", "0.18.0", 0},
+		{"v1.0", "0.23.0", 1},
+		{"0.9.12", "0.10.0", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			if got := compareVersions(tt.a, tt.b); got != tt.want {
+				t.Errorf("compareVersions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,354 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var (
+	// scheduleTriggerTypes are the types of the triggers starting a flow on a cron schedule.
+	scheduleTriggerTypes = map[string]bool{
+		"io.kestra.plugin.core.trigger.Schedule":        true,
+		"io.kestra.core.models.triggers.types.Schedule": true,
+	}
+	// webhookTriggerTypes are the types of the triggers starting a flow on an HTTP request.
+	webhookTriggerTypes = map[string]bool{
+		"io.kestra.plugin.core.trigger.Webhook":        true,
+		"io.kestra.core.models.triggers.types.Webhook": true,
+	}
+)
+
+// TriggerCondition is a condition of a trigger. The properties specific to the condition type are kept
+// in Properties, as for FlowTask.
+type TriggerCondition struct {
+	Type string `json:"type,omitempty" structs:"type,omitempty"`
+
+	// Properties holds every other property of the condition. Numbers are decoded as json.Number.
+	Properties map[string]interface{} `json:"-" structs:"-"`
+}
+
+type triggerCondition TriggerCondition
+
+// UnmarshalJSON decodes a condition, keeping its untyped properties.
+func (c *TriggerCondition) UnmarshalJSON(data []byte) error {
+	*c = TriggerCondition{}
+	properties, err := unmarshalWithExtra(data, (*triggerCondition)(c))
+	c.Properties = properties
+	return err
+}
+
+// MarshalJSON encodes a condition with its Properties.
+func (c TriggerCondition) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(triggerCondition(c), c.Properties)
+}
+
+// ScheduleBackfill is the backfill of a Schedule trigger: the past schedules to execute.
+type ScheduleBackfill struct {
+	// Start and End are ISO 8601 date-times. End is empty for a backfill up to now.
+	Start string `json:"start,omitempty" structs:"start,omitempty"`
+	End   string `json:"end,omitempty" structs:"end,omitempty"`
+
+	// Extra holds the properties not modeled by ScheduleBackfill.
+	Extra map[string]interface{} `json:"-" structs:"-"`
+}
+
+type scheduleBackfill ScheduleBackfill
+
+// UnmarshalJSON decodes a backfill, keeping its unknown properties in Extra.
+func (b *ScheduleBackfill) UnmarshalJSON(data []byte) error {
+	*b = ScheduleBackfill{}
+	extra, err := unmarshalWithExtra(data, (*scheduleBackfill)(b))
+	b.Extra = extra
+	return err
+}
+
+// MarshalJSON encodes a backfill with its Extra properties.
+func (b ScheduleBackfill) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(scheduleBackfill(b), b.Extra)
+}
+
+// ScheduleTrigger holds the properties of a Schedule trigger, which starts a flow on a cron schedule.
+type ScheduleTrigger struct {
+	Cron string `json:"cron,omitempty" structs:"cron,omitempty"`
+	// Timezone is the IANA time zone of Cron, e.g. "Europe/Paris". It defaults to the server time zone.
+	Timezone string `json:"timezone,omitempty" structs:"timezone,omitempty"`
+	// WithSeconds is set when Cron starts with a seconds field.
+	WithSeconds bool              `json:"withSeconds,omitempty" structs:"withSeconds,omitempty"`
+	Backfill    *ScheduleBackfill `json:"backfill,omitempty" structs:"backfill,omitempty"`
+	// Inputs are the inputs of the executions started by the trigger.
+	Inputs map[string]interface{} `json:"inputs,omitempty" structs:"inputs,omitempty"`
+	// RecoverMissedSchedules is "ALL", "LAST" or "NONE".
+	RecoverMissedSchedules string             `json:"recoverMissedSchedules,omitempty" structs:"recoverMissedSchedules,omitempty"`
+	Conditions             []TriggerCondition `json:"conditions,omitempty" structs:"conditions,omitempty"`
+
+	// Extra holds the properties not modeled by ScheduleTrigger.
+	Extra map[string]interface{} `json:"-" structs:"-"`
+}
+
+type scheduleTrigger ScheduleTrigger
+
+// UnmarshalJSON decodes a Schedule trigger, keeping its unknown properties in Extra.
+func (s *ScheduleTrigger) UnmarshalJSON(data []byte) error {
+	*s = ScheduleTrigger{}
+	extra, err := unmarshalWithExtra(data, (*scheduleTrigger)(s))
+	s.Extra = extra
+	return err
+}
+
+// MarshalJSON encodes a Schedule trigger with its Extra properties.
+func (s ScheduleTrigger) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(scheduleTrigger(s), s.Extra)
+}
+
+// NextFireTimes returns the n next times strictly after t when the trigger fires, computed locally in
+// its time zone, or in UTC if it has none. Conditions are not evaluated.
+func (s *ScheduleTrigger) NextFireTimes(t time.Time, n int) ([]time.Time, error) {
+	cron, err := ParseCron(s.Cron, s.WithSeconds)
+	if err != nil {
+		return nil, err
+	}
+	loc := time.UTC
+	if s.Timezone != "" {
+		if loc, err = time.LoadLocation(s.Timezone); err != nil {
+			return nil, fmt.Errorf("kestra: timezone %q: %w", s.Timezone, err)
+		}
+	}
+	return cron.NextN(t.In(loc), n), nil
+}
+
+// WebhookTrigger holds the properties of a Webhook trigger, which starts a flow when its URL is called.
+type WebhookTrigger struct {
+	// Key is the secret part of the URL of the webhook.
+	Key        string             `json:"key,omitempty" structs:"key,omitempty"`
+	Conditions []TriggerCondition `json:"conditions,omitempty" structs:"conditions,omitempty"`
+
+	// Extra holds the properties not modeled by WebhookTrigger.
+	Extra map[string]interface{} `json:"-" structs:"-"`
+}
+
+type webhookTrigger WebhookTrigger
+
+// UnmarshalJSON decodes a Webhook trigger, keeping its unknown properties in Extra.
+func (w *WebhookTrigger) UnmarshalJSON(data []byte) error {
+	*w = WebhookTrigger{}
+	extra, err := unmarshalWithExtra(data, (*webhookTrigger)(w))
+	w.Extra = extra
+	return err
+}
+
+// MarshalJSON encodes a Webhook trigger with its Extra properties.
+func (w WebhookTrigger) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(webhookTrigger(w), w.Extra)
+}
+
+// FlowPrecondition is an upstream flow of the preconditions of a Flow trigger.
+type FlowPrecondition struct {
+	Namespace string   `json:"namespace,omitempty" structs:"namespace,omitempty"`
+	FlowID    string   `json:"flowId,omitempty" structs:"flowId,omitempty"`
+	States    []string `json:"states,omitempty" structs:"states,omitempty"`
+
+	// Extra holds the properties not modeled by FlowPrecondition.
+	Extra map[string]interface{} `json:"-" structs:"-"`
+}
+
+type flowPrecondition FlowPrecondition
+
+// UnmarshalJSON decodes a precondition, keeping its unknown properties in Extra.
+func (p *FlowPrecondition) UnmarshalJSON(data []byte) error {
+	*p = FlowPrecondition{}
+	extra, err := unmarshalWithExtra(data, (*flowPrecondition)(p))
+	p.Extra = extra
+	return err
+}
+
+// MarshalJSON encodes a precondition with its Extra properties.
+func (p FlowPrecondition) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(flowPrecondition(p), p.Extra)
+}
+
+// FlowPreconditions are the executions of upstream flows a Flow trigger waits for.
+type FlowPreconditions struct {
+	ID    string             `json:"id,omitempty" structs:"id,omitempty"`
+	Flows []FlowPrecondition `json:"flows,omitempty" structs:"flows,omitempty"`
+
+	// Extra holds the properties not modeled by FlowPreconditions, such as where and timeWindow.
+	Extra map[string]interface{} `json:"-" structs:"-"`
+}
+
+type flowPreconditions FlowPreconditions
+
+// UnmarshalJSON decodes preconditions, keeping their unknown properties in Extra.
+func (p *FlowPreconditions) UnmarshalJSON(data []byte) error {
+	*p = FlowPreconditions{}
+	extra, err := unmarshalWithExtra(data, (*flowPreconditions)(p))
+	p.Extra = extra
+	return err
+}
+
+// MarshalJSON encodes preconditions with their Extra properties.
+func (p FlowPreconditions) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(flowPreconditions(p), p.Extra)
+}
+
+// FlowExecutionTrigger holds the properties of a Flow trigger, which starts a flow when executions
+// of other flows end.
+type FlowExecutionTrigger struct {
+	// States are the execution states the trigger reacts to.
+	States        []string           `json:"states,omitempty" structs:"states,omitempty"`
+	Conditions    []TriggerCondition `json:"conditions,omitempty" structs:"conditions,omitempty"`
+	Preconditions *FlowPreconditions `json:"preconditions,omitempty" structs:"preconditions,omitempty"`
+	// Inputs are the inputs of the executions started by the trigger.
+	Inputs map[string]interface{} `json:"inputs,omitempty" structs:"inputs,omitempty"`
+
+	// Extra holds the properties not modeled by FlowExecutionTrigger.
+	Extra map[string]interface{} `json:"-" structs:"-"`
+}
+
+type flowExecutionTrigger FlowExecutionTrigger
+
+// UnmarshalJSON decodes a Flow trigger, keeping its unknown properties in Extra.
+func (f *FlowExecutionTrigger) UnmarshalJSON(data []byte) error {
+	*f = FlowExecutionTrigger{}
+	extra, err := unmarshalWithExtra(data, (*flowExecutionTrigger)(f))
+	f.Extra = extra
+	return err
+}
+
+// MarshalJSON encodes a Flow trigger with its Extra properties.
+func (f FlowExecutionTrigger) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(flowExecutionTrigger(f), f.Extra)
+}
+
+// UpstreamFlows returns the flows named by the trigger, in its ExecutionFlow conditions and in its preconditions.
+func (f *FlowExecutionTrigger) UpstreamFlows() []FlowRef {
+	var refs []FlowRef
+	for _, condition := range f.Conditions {
+		if !executionFlowConditionTypes[condition.Type] {
+			continue
+		}
+		namespace, _ := condition.Properties["namespace"].(string)
+		id, _ := condition.Properties["flowId"].(string)
+		if namespace != "" && id != "" {
+			refs = append(refs, FlowRef{Namespace: namespace, ID: id})
+		}
+	}
+	if f.Preconditions != nil {
+		for _, flow := range f.Preconditions.Flows {
+			if flow.Namespace != "" && flow.FlowID != "" {
+				refs = append(refs, FlowRef{Namespace: flow.Namespace, ID: flow.FlowID})
+			}
+		}
+	}
+	return refs
+}
+
+// PollingTrigger holds the properties of any other trigger, such as the triggers of plugins polling
+// an external system.
+type PollingTrigger struct {
+	// Interval is the ISO 8601 duration between two polls, e.g. "PT1M". Empty means the plugin default.
+	Interval   string             `json:"interval,omitempty" structs:"interval,omitempty"`
+	Conditions []TriggerCondition `json:"conditions,omitempty" structs:"conditions,omitempty"`
+
+	// Extra holds the properties specific to the trigger type.
+	Extra map[string]interface{} `json:"-" structs:"-"`
+}
+
+type pollingTrigger PollingTrigger
+
+// UnmarshalJSON decodes a polling trigger, keeping its type-specific properties in Extra.
+func (p *PollingTrigger) UnmarshalJSON(data []byte) error {
+	*p = PollingTrigger{}
+	extra, err := unmarshalWithExtra(data, (*pollingTrigger)(p))
+	p.Extra = extra
+	return err
+}
+
+// MarshalJSON encodes a polling trigger with its Extra properties.
+func (p PollingTrigger) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(pollingTrigger(p), p.Extra)
+}
+
+// Schedule returns the properties of a Schedule trigger, or nil if t is another kind of trigger.
+func (t FlowTrigger) Schedule() (*ScheduleTrigger, error) {
+	if !scheduleTriggerTypes[t.Type] {
+		return nil, nil
+	}
+	schedule := new(ScheduleTrigger)
+	return schedule, t.decodeProperties(schedule)
+}
+
+// Webhook returns the properties of a Webhook trigger, or nil if t is another kind of trigger.
+func (t FlowTrigger) Webhook() (*WebhookTrigger, error) {
+	if !webhookTriggerTypes[t.Type] {
+		return nil, nil
+	}
+	webhook := new(WebhookTrigger)
+	return webhook, t.decodeProperties(webhook)
+}
+
+// FlowExecution returns the properties of a Flow trigger, or nil if t is another kind of trigger.
+func (t FlowTrigger) FlowExecution() (*FlowExecutionTrigger, error) {
+	if !flowTriggerTypes[t.Type] {
+		return nil, nil
+	}
+	flow := new(FlowExecutionTrigger)
+	return flow, t.decodeProperties(flow)
+}
+
+// Polling returns the properties of a trigger that is neither a Schedule, a Webhook nor a Flow trigger,
+// or nil if t is one of them.
+func (t FlowTrigger) Polling() (*PollingTrigger, error) {
+	if scheduleTriggerTypes[t.Type] || webhookTriggerTypes[t.Type] || flowTriggerTypes[t.Type] {
+		return nil, nil
+	}
+	polling := new(PollingTrigger)
+	return polling, t.decodeProperties(polling)
+}
+
+// decodeProperties decodes the Properties of the trigger into v.
+func (t FlowTrigger) decodeProperties(v interface{}) error {
+	data, err := json.Marshal(t.Properties)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("kestra: trigger %q: %w", t.ID, err)
+	}
+	return nil
+}
+
+// NextScheduledRuns returns the n next times strictly after t when the enabled Schedule triggers of the
+// flow start it, computed locally. It returns no time for a disabled flow, or if n is not positive.
+func (f *Flow) NextScheduledRuns(t time.Time, n int) ([]time.Time, error) {
+	if f.Disabled || n <= 0 {
+		return nil, nil
+	}
+
+	var times []time.Time
+	for _, trigger := range f.Triggers {
+		schedule, err := trigger.Schedule()
+		if err != nil {
+			return nil, err
+		}
+		if schedule == nil || trigger.Disabled {
+			continue
+		}
+		next, err := schedule.NextFireTimes(t, n)
+		if err != nil {
+			return nil, fmt.Errorf("kestra: trigger %q: %w", trigger.ID, err)
+		}
+		times = append(times, next...)
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	// triggers firing at the same instant start a single run each, but are shown once
+	unique := times[:0]
+	for _, next := range times {
+		if len(unique) == 0 || !unique[len(unique)-1].Equal(next) {
+			unique = append(unique, next)
+		}
+	}
+	return unique[:min(n, len(unique))], nil
+}
@@ -0,0 +1,166 @@
+package v1
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFlowTrigger_Typed(t *testing.T) {
+	decode := func(data string) FlowTrigger {
+		var trigger FlowTrigger
+		if err := json.Unmarshal([]byte(data), &trigger); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		return trigger
+	}
+
+	schedule := decode(`{"id":"daily","type":"io.kestra.plugin.core.trigger.Schedule","cron":"0 9 * * *","timezone":"Europe/Paris",` +
+		`"backfill":{"start":"2024-01-01T00:00:00Z"},"inputs":{"name":"world"},"stopAfter":["FAILED"]}`)
+	webhook := decode(`{"id":"hook","type":"io.kestra.plugin.core.trigger.Webhook","key":"secret"}`)
+	flow := decode(`{"id":"upstream","type":"io.kestra.plugin.core.trigger.Flow","states":["SUCCESS"],` +
+		`"conditions":[{"type":"io.kestra.plugin.core.condition.ExecutionFlow","namespace":"company","flowId":"extract"}],` +
+		`"preconditions":{"id":"both","flows":[{"namespace":"company","flowId":"load","states":["SUCCESS"]}]}}`)
+	polling := decode(`{"id":"poll","type":"io.kestra.plugin.fs.sftp.Trigger","interval":"PT5M","host":"localhost"}`)
+
+	gotSchedule, err := schedule.Schedule()
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	wantSchedule := &ScheduleTrigger{
+		Cron:     "0 9 * * *",
+		Timezone: "Europe/Paris",
+		Backfill: &ScheduleBackfill{Start: "2024-01-01T00:00:00Z"},
+		Inputs:   map[string]interface{}{"name": "world"},
+		Extra:    map[string]interface{}{"stopAfter": []interface{}{"FAILED"}},
+	}
+	if !reflect.DeepEqual(gotSchedule, wantSchedule) {
+		t.Errorf("Schedule() got = %+v, want %+v", gotSchedule, wantSchedule)
+	}
+
+	gotWebhook, err := webhook.Webhook()
+	if err != nil || gotWebhook == nil || gotWebhook.Key != "secret" {
+		t.Errorf("Webhook() got = %+v, %v, want key secret", gotWebhook, err)
+	}
+
+	gotFlow, err := flow.FlowExecution()
+	if err != nil {
+		t.Fatalf("FlowExecution() error = %v", err)
+	}
+	wantUpstream := []FlowRef{{Namespace: "company", ID: "extract"}, {Namespace: "company", ID: "load"}}
+	if !reflect.DeepEqual(gotFlow.UpstreamFlows(), wantUpstream) || !reflect.DeepEqual(gotFlow.States, []string{"SUCCESS"}) {
+		t.Errorf("FlowExecution() got = %+v, want upstream flows %v", gotFlow, wantUpstream)
+	}
+
+	gotPolling, err := polling.Polling()
+	if err != nil || gotPolling == nil || gotPolling.Interval != "PT5M" || gotPolling.Extra["host"] != "localhost" {
+		t.Errorf("Polling() got = %+v, %v, want interval PT5M", gotPolling, err)
+	}
+
+	tests := []struct {
+		name    string
+		trigger FlowTrigger
+		get     func(FlowTrigger) (interface{}, error)
+	}{
+		{"should not decode a webhook as a schedule", webhook, func(t FlowTrigger) (interface{}, error) { return t.Schedule() }},
+		{"should not decode a schedule as a webhook", schedule, func(t FlowTrigger) (interface{}, error) { return t.Webhook() }},
+		{"should not decode a polling trigger as a flow trigger", polling, func(t FlowTrigger) (interface{}, error) { return t.FlowExecution() }},
+		{"should not decode a flow trigger as a polling trigger", flow, func(t FlowTrigger) (interface{}, error) { return t.Polling() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get(tt.trigger)
+			if err != nil || !reflect.ValueOf(got).IsNil() {
+				t.Errorf("got = %+v, %v, want nil", got, err)
+			}
+		})
+	}
+
+	invalid := decode(`{"id":"daily","type":"io.kestra.plugin.core.trigger.Schedule","cron":["0 9 * * *"]}`)
+	if _, err := invalid.Schedule(); err == nil {
+		t.Errorf("Schedule() of an invalid trigger error = nil, want error")
+	}
+}
+
+func TestScheduleTrigger_NextFireTimes(t *testing.T) {
+	after := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule ScheduleTrigger
+		want     []string
+		wantErr  bool
+	}{
+		{"should use UTC by default", ScheduleTrigger{Cron: "0 9 * * *"}, []string{"2024-01-02T09:00:00Z", "2024-01-03T09:00:00Z"}, false},
+		{"should use the time zone", ScheduleTrigger{Cron: "0 9 * * *", Timezone: "America/New_York"},
+			[]string{"2024-01-01T09:00:00-05:00", "2024-01-02T09:00:00-05:00"}, false},
+		{"should use seconds", ScheduleTrigger{Cron: "15 0 12 * * *", WithSeconds: true},
+			[]string{"2024-01-01T12:00:15Z", "2024-01-02T12:00:15Z"}, false},
+		{"should not use an invalid cron", ScheduleTrigger{Cron: "0 9 * *"}, nil, true},
+		{"should not use an invalid time zone", ScheduleTrigger{Cron: "0 9 * * *", Timezone: "Mars/Olympus"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.schedule.NextFireTimes(after, 2)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NextFireTimes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var formatted []string
+			for _, next := range got {
+				formatted = append(formatted, next.Format(time.RFC3339))
+			}
+			if !reflect.DeepEqual(formatted, tt.want) {
+				t.Errorf("NextFireTimes() got = %v, want %v", formatted, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlow_NextScheduledRuns(t *testing.T) {
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	trigger := func(id, cron string, disabled bool) FlowTrigger {
+		return FlowTrigger{ID: id, Type: "io.kestra.plugin.core.trigger.Schedule", Disabled: disabled,
+			Properties: map[string]interface{}{"cron": cron}}
+	}
+	webhook := FlowTrigger{ID: "hook", Type: "io.kestra.plugin.core.trigger.Webhook"}
+
+	tests := []struct {
+		name    string
+		flow    Flow
+		want    []time.Time
+		wantErr bool
+	}{
+		{"should merge the schedules", Flow{Triggers: []FlowTrigger{trigger("frequent", "0 */6 * * *", false), trigger("daily", "30 1 * * *", false), webhook}},
+			[]time.Time{after.Add(90 * time.Minute), after.Add(6 * time.Hour), after.Add(12 * time.Hour)}, false},
+		{"should merge equal times", Flow{Triggers: []FlowTrigger{trigger("first", "0 6 * * *", false), trigger("second", "0 6 * * *", false)}},
+			[]time.Time{after.Add(6 * time.Hour), after.Add(30 * time.Hour), after.Add(54 * time.Hour)}, false},
+		{"should skip disabled triggers", Flow{Triggers: []FlowTrigger{trigger("hourly", "0 * * * *", true), trigger("daily", "0 0 * * *", false)}},
+			[]time.Time{after.Add(24 * time.Hour), after.Add(48 * time.Hour), after.Add(72 * time.Hour)}, false},
+		{"should skip disabled flows", Flow{Disabled: true, Triggers: []FlowTrigger{trigger("daily", "0 0 * * *", false)}}, nil, false},
+		{"should fail on an invalid cron", Flow{Triggers: []FlowTrigger{trigger("daily", "never", false)}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.flow.NextScheduledRuns(after, 3)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NextScheduledRuns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("NextScheduledRuns() got = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("NextScheduledRuns()[%d] got = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	flow := Flow{Triggers: []FlowTrigger{trigger("daily", "0 0 * * *", false)}}
+	for _, n := range []int{0, -1} {
+		if got, err := flow.NextScheduledRuns(after, n); got != nil || err != nil {
+			t.Errorf("NextScheduledRuns(%d) got = %v, error = %v, want nil", n, got, err)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseFlowYAML parses the YAML source of a flow, as stored by Kestra, into a Flow.
+// The returned flow keeps source as its Source.
+func ParseFlowYAML(source string) (*Flow, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(source), &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 {
+		return nil, errors.New("kestra: empty flow source")
+	}
+
+	value, err := yamlNodeValue(&doc)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := value.(map[string]interface{}); !ok {
+		return nil, errors.New("kestra: flow source is not a YAML mapping")
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	flow := new(Flow)
+	if err := json.Unmarshal(data, flow); err != nil {
+		return nil, err
+	}
+	flow.Source = source
+
+	return flow, nil
+}
+
+// serverManagedKeys are the flow properties set by the server, left out of rendered sources.
+var serverManagedKeys = map[string]bool{"revision": true, "deleted": true, "source": true}
+
+// MarshalFlowYAML renders flow as a canonical YAML source, suitable for FlowService.Update or SyncNamespace.
+// Properties are written in the order of the Flow fields, followed by the unknown properties sorted by name,
+// and the properties set by the server (revision, deleted, source) are left out.
+func MarshalFlowYAML(flow *Flow) (string, error) {
+	data, err := json.Marshal(flow)
+	if err != nil {
+		return "", err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	node, err := jsonYAMLNode(dec)
+	if err != nil {
+		return "", err
+	}
+	content := node.Content[:0]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if !serverManagedKeys[node.Content[i].Value] {
+			content = append(content, node.Content[i], node.Content[i+1])
+		}
+	}
+	node.Content = content
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// jsonYAMLNode reads the next JSON value of dec and returns the equivalent YAML node, keeping the order
+// of object keys. dec must use json.Number.
+func jsonYAMLNode(dec *json.Decoder) (*yaml.Node, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := jsonYAMLNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)}, value)
+			}
+			_, err := dec.Token() // }
+			return node, err
+		case '[':
+			node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for dec.More() {
+				value, err := jsonYAMLNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, value)
+			}
+			_, err := dec.Token() // ]
+			return node, err
+		}
+	case string:
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}
+		if strings.Contains(t, "\n") {
+			node.Style = yaml.LiteralStyle
+		}
+		return node, nil
+	case json.Number:
+		tag := "!!int"
+		if _, err := t.Int64(); err != nil {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: t.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(t)}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+
+	return nil, io.ErrUnexpectedEOF
+}
+
+// yamlNodeValue converts a YAML node into the value encoding/json would decode from the equivalent JSON
+// document: maps, slices, strings, booleans, nil and json.Number. Scalars such as timestamps are kept as
+// written in the source, instead of being converted to time.Time.
+func yamlNodeValue(n *yaml.Node) (interface{}, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return yamlNodeValue(n.Content[0])
+
+	case yaml.AliasNode:
+		return yamlNodeValue(n.Alias)
+
+	case yaml.MappingNode:
+		m := make(map[string]interface{}, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if key.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("kestra: line %d: unsupported mapping key", key.Line)
+			}
+			v, err := yamlNodeValue(value)
+			if err != nil {
+				return nil, err
+			}
+			m[key.Value] = v
+		}
+		return m, nil
+
+	case yaml.SequenceNode:
+		s := make([]interface{}, 0, len(n.Content))
+		for _, item := range n.Content {
+			v, err := yamlNodeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, v)
+		}
+		return s, nil
+
+	case yaml.ScalarNode:
+		switch n.ShortTag() {
+		case "!!null":
+			return nil, nil
+		case "!!bool":
+			var b bool
+			if err := n.Decode(&b); err != nil {
+				return nil, err
+			}
+			return b, nil
+		case "!!int":
+			var i int64
+			if err := n.Decode(&i); err != nil {
+				// too large for an int64, keep it as written
+				return n.Value, nil
+			}
+			return json.Number(strconv.FormatInt(i, 10)), nil
+		case "!!float":
+			var f float64
+			if err := n.Decode(&f); err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+				return n.Value, nil
+			}
+			return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), nil
+		default:
+			return n.Value, nil
+		}
+	}
+
+	return nil, fmt.Errorf("kestra: line %d: unsupported YAML node", n.Line)
+}
+
+// joinYAMLDocuments returns the multi-document YAML body sent to the bulk endpoints for sources, one
+// document per source in order, as the server maps its results back to the sources by index. A leading
+// document marker is stripped, and a source holding several documents is rejected.
+func joinYAMLDocuments(sources []string) (string, error) {
+	documents := make([]string, len(sources))
+	for i, source := range sources {
+		source = strings.TrimSpace(source)
+		if first, rest, _ := strings.Cut(source, "\n"); strings.TrimSpace(first) == "---" {
+			source = strings.TrimSpace(rest)
+		}
+
+		count := 0
+		decoder := yaml.NewDecoder(strings.NewReader(source))
+		for {
+			var doc yaml.Node
+			// invalid sources are left to the server, which reports them
+			if err := decoder.Decode(&doc); err != nil {
+				break
+			}
+			if doc.Kind != 0 {
+				count++
+			}
+		}
+		if count > 1 {
+			return "", fmt.Errorf("kestra: source %d: holds %d YAML documents, want one", i, count)
+		}
+		documents[i] = source
+	}
+	return strings.Join(documents, "\n---\n") + "\n", nil
+}
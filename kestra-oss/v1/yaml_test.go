@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFlowYAML(t *testing.T) {
+	source := `id: hello_world
+namespace: tutorial
+revision: 3
+description: Hello World
+inputs:
+  - id: service_name
+    type: STRING
+    defaults: "2024-07-15"
+tasks:
+  - id: log
+    type: io.kestra.plugin.core.log.Log
+    message: Hello World
+`
+
+	tests := []struct {
+		name    string
+		source  string
+		want    *Flow
+		wantErr bool
+	}{
+		{"should parse a flow", source,
+			&Flow{ID: "hello_world", Namespace: "tutorial", Revision: "3", Description: "Hello World",
+				Tasks: []FlowTask{{ID: "log", Type: "io.kestra.plugin.core.log.Log",
+					Properties: map[string]interface{}{"message": "Hello World"},
+					Raw:        json.RawMessage(`{"id":"log","message":"Hello World","type":"io.kestra.plugin.core.log.Log"}`)}},
+				Inputs: []FlowInput{{ID: "service_name", Type: "STRING", Defaults: "2024-07-15"}},
+				Source: source},
+			false,
+		},
+		{"should parse a JSON flow", `{"id":"hello_world","namespace":"tutorial"}`,
+			&Flow{ID: "hello_world", Namespace: "tutorial", Source: `{"id":"hello_world","namespace":"tutorial"}`},
+			false,
+		},
+		{"should reject an empty source", "", nil, true},
+		{"should reject a list", "- id: hello_world", nil, true},
+		{"should reject invalid YAML", "id: [hello", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFlowYAML(tt.source)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFlowYAML() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFlowYAML() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalFlowYAML(t *testing.T) {
+	source := `id: etl
+namespace: company.team
+labels:
+  - key: env
+    value: "true"
+variables:
+  batch: 500
+  ratio: 0.5
+  empty: null
+tasks:
+  - id: script
+    type: io.kestra.plugin.scripts.python.Script
+    retry:
+      type: constant
+      maxAttempt: 3
+      interval: PT10S
+    script: |
+      print("hello")
+      print("world")
+sla:
+  - id: max
+    type: MAX_DURATION
+`
+	flow, err := ParseFlowYAML(source)
+	if err != nil {
+		t.Fatalf("ParseFlowYAML() error = %v", err)
+	}
+	flow.Revision = "3"
+
+	got, err := MarshalFlowYAML(flow)
+	if err != nil {
+		t.Fatalf("MarshalFlowYAML() error = %v", err)
+	}
+	// variables are a map, so their keys are sorted
+	want := strings.Replace(source, "  batch: 500\n  ratio: 0.5\n  empty: null\n", "  batch: 500\n  empty: null\n  ratio: 0.5\n", 1)
+	if got != want {
+		t.Errorf("MarshalFlowYAML() got =\n%s\nwant\n%s", got, want)
+	}
+}